@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/K-Yo/splunk_exporter/exporter"
+)
+
+const collectorSuccessMetric = "splunk_exporter_collector_success"
+
+// collectedSample is the JSON representation of one measure
+type collectedSample struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// collectedFamily is the JSON representation of one metric and its measures
+type collectedFamily struct {
+	Name    string            `json:"name"`
+	Help    string            `json:"help"`
+	Type    string            `json:"type"`
+	Samples []collectedSample `json:"samples"`
+}
+
+// collectorStatus is the JSON representation of how a collector did
+type collectorStatus struct {
+	Success bool     `json:"success"`
+	Errors  []string `json:"errors,omitempty"` // errors logged by the collector
+}
+
+// collectResult is the JSON document printed by the collect command
+type collectResult struct {
+	Metrics    []collectedFamily          `json:"metrics"`
+	Collectors map[string]collectorStatus `json:"collectors"`
+}
+
+// collectorErrors is a logger recording the errors logged by each collector, before passing logs on
+// collectors log with exporter.CollectorLogKey, errors logged by the exporter itself are not recorded.
+type collectorErrors struct {
+	next   log.Logger
+	mu     sync.Mutex // guards errors
+	errors map[string][]string
+}
+
+func newCollectorErrors(next log.Logger) *collectorErrors {
+	return &collectorErrors{next: next, errors: make(map[string][]string)}
+}
+
+func (c *collectorErrors) Log(keyvals ...interface{}) error {
+	var isError bool
+	var collector, msg, cause string
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch keyvals[i] {
+		case level.Key():
+			isError = keyvals[i+1] == level.ErrorValue()
+		case exporter.CollectorLogKey:
+			collector = fmt.Sprint(keyvals[i+1])
+		case "msg":
+			msg = fmt.Sprint(keyvals[i+1])
+		case "err", "error":
+			cause = fmt.Sprint(keyvals[i+1])
+		}
+	}
+	if isError && collector != "" {
+		if cause != "" {
+			msg = msg + ": " + cause
+		}
+		c.mu.Lock()
+		c.errors[collector] = append(c.errors[collector], msg)
+		c.mu.Unlock()
+	}
+	return c.next.Log(keyvals...)
+}
+
+// of returns the errors logged by the named collector
+func (c *collectorErrors) of(collector string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.errors[collector]
+}
+
+// collect scrapes the configured Splunk instance once and prints the result on stdout
+// it returns a non-zero exit code if a collector failed
+func collect(logger log.Logger) int {
+	errs := newCollectorErrors(logger)
+	exp, err := exporter.New(splunkOpts(sc.C), errs, sc.C.Metrics, sc.C.Collectors)
+	if err != nil {
+		level.Error(logger).Log("msg", "could not create exporter", "err", err)
+		return 1
+	}
+	if len(*collectCollectors) > 0 {
+		if err := exp.EnableCollectors(*collectCollectors...); err != nil {
+			level.Error(logger).Log("msg", "invalid collector selection", "err", err)
+			return 1
+		}
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(exp)
+	families, err := reg.Gather()
+	if err != nil {
+		level.Error(logger).Log("msg", "error while gathering metrics", "err", err)
+		return 1
+	}
+
+	collectors := collectorsStatus(families, errs)
+	switch *collectFormat {
+	case "json":
+		err = printJSON(families, collectors)
+	default:
+		err = printText(families, collectors)
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to print metrics", "err", err)
+		return 1
+	}
+
+	for _, status := range collectors {
+		if !status.Success {
+			return 1
+		}
+	}
+	return 0
+}
+
+// collectorsStatus reads the success of every collector from gathered metrics, with the errors it logged
+func collectorsStatus(families []*dto.MetricFamily, errs *collectorErrors) map[string]collectorStatus {
+	status := make(map[string]collectorStatus)
+	for _, f := range families {
+		if f.GetName() != collectorSuccessMetric {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "collector" {
+					status[l.GetValue()] = collectorStatus{
+						Success: m.GetGauge().GetValue() == 1,
+						Errors:  errs.of(l.GetValue()),
+					}
+				}
+			}
+		}
+	}
+	return status
+}
+
+// printText prints metrics in prometheus exposition format, followed by collectors status as comments
+func printText(families []*dto.MetricFamily, collectors map[string]collectorStatus) error {
+	for _, f := range families {
+		if _, err := expfmt.MetricFamilyToText(os.Stdout, f); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(collectors) {
+		status := "ok"
+		switch c := collectors[name]; {
+		case !c.Success && len(c.Errors) > 0:
+			status = "failed: " + strings.Join(c.Errors, "; ")
+		case !c.Success:
+			status = "failed, see logs for details"
+		}
+		fmt.Fprintf(os.Stdout, "# collector %s: %s\n", name, status)
+	}
+	return nil
+}
+
+// printJSON prints metrics and collectors status as a JSON document
+func printJSON(families []*dto.MetricFamily, collectors map[string]collectorStatus) error {
+	result := collectResult{
+		Metrics:    make([]collectedFamily, 0, len(families)),
+		Collectors: collectors,
+	}
+	for _, f := range families {
+		family := collectedFamily{
+			Name: f.GetName(),
+			Help: f.GetHelp(),
+			Type: f.GetType().String(),
+		}
+		for _, m := range f.GetMetric() {
+			sample := collectedSample{Labels: make(map[string]string)}
+			for _, l := range m.GetLabel() {
+				sample.Labels[l.GetName()] = l.GetValue()
+			}
+			switch {
+			case m.Gauge != nil:
+				sample.Value = m.GetGauge().GetValue()
+			case m.Counter != nil:
+				sample.Value = m.GetCounter().GetValue()
+			case m.Untyped != nil:
+				sample.Value = m.GetUntyped().GetValue()
+			}
+			family.Samples = append(family.Samples, sample)
+		}
+		result.Metrics = append(result.Metrics, family)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// sortedKeys returns the keys of m in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
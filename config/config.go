@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,13 +17,61 @@ type Metric struct {
 	Name  string `yaml:"name"`
 }
 
+// Indexes configures the collector of data/indexes measures
+type Indexes struct {
+	Generic bool `yaml:"generic"` // also export every numeric field as splunk_exporter_index_<field>, defaults to false
+}
+
+// Forwarders configures the collector of forwarder connections
+type Forwarders struct {
+	Max int `yaml:"max"` // maximum number of forwarders exported, most recently seen first, defaults to 1000
+}
+
+// Deployment configures the collector of deployment server clients
+type Deployment struct {
+	PhoneHomeThreshold time.Duration `yaml:"phone_home_threshold"` // clients not phoned home for longer are counted as missing, defaults to 1h
+}
+
+// Scheduler configures the collector of scheduled searches
+type Scheduler struct {
+	MaxSearches int `yaml:"max_searches"` // maximum number of saved searches exported, most skipped first for runs, by app and name for dispatch, defaults to 500
+}
+
+// Messages configures the collector of Splunk messages, names accept shell patterns like LM_*
+type Messages struct {
+	Allow []string `yaml:"allow"` // only messages with a matching name are exported, all when empty
+	Deny  []string `yaml:"deny"`  // messages with a matching name are not exported, even if allowed
+}
+
+// Jobs configures the collector of search jobs
+type Jobs struct {
+	MaxUsers int `yaml:"max_users"` // maximum number of users exported, others are summed as "_other", defaults to 50
+}
+
+// Users configures the collector of users and sessions
+type Users struct {
+	MaxUsers int `yaml:"max_users"` // maximum number of users exported with their sessions, others are summed as "_other", defaults to 50
+}
+
+// Collectors holds settings specific to each collector
+type Collectors struct {
+	Indexes    Indexes    `yaml:"indexes"`
+	Forwarders Forwarders `yaml:"forwarders"`
+	Deployment Deployment `yaml:"deployment"`
+	Scheduler  Scheduler  `yaml:"scheduler"`
+	Messages   Messages   `yaml:"messages"`
+	Jobs       Jobs       `yaml:"jobs"`
+	Users      Users      `yaml:"users"`
+}
+
 type Config struct {
-	URL      string   `yaml:"url"`
-	Token    string   `yaml:"token"`
-	Username string   `yaml:"username"`
-	Password string   `yaml:"password"`
-	Insecure bool     `yaml:"insecure"` // defaults to false
-	Metrics  []Metric `yaml:"metrics"`
+	URL        string     `yaml:"url"`
+	Token      string     `yaml:"token"`
+	Username   string     `yaml:"username"`
+	Password   string     `yaml:"password"`
+	Insecure   bool       `yaml:"insecure"` // defaults to false
+	Metrics    []Metric   `yaml:"metrics"`
+	Collectors Collectors `yaml:"collectors"`
 }
 
 type SafeConfig struct {
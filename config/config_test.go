@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -47,3 +48,36 @@ func TestLoadConfigUser(t *testing.T) {
 		t.Errorf("Error loading config %v: %v", "splunk_exporter-good.yml", err)
 	}
 }
+
+// TestLoadConfigCollectors
+// Given
+//
+//	A valid config file with collectors settings
+//
+// When
+//
+//	reloading the config
+//
+// Then
+//
+//	Collectors settings are loaded
+func TestLoadConfigCollectors(t *testing.T) {
+	sc := NewSafeConfig(prometheus.NewRegistry())
+
+	err := sc.ReloadConfig("testdata/splunk_exporter-collectors-good.yml", nil)
+	if err != nil {
+		t.Errorf("Error loading config %v: %v", "splunk_exporter-collectors-good.yml", err)
+	}
+	if !sc.C.Collectors.Indexes.Generic {
+		t.Errorf("Expected collectors.indexes.generic to be true")
+	}
+	if sc.C.Collectors.Forwarders.Max != 50 {
+		t.Errorf("Expected collectors.forwarders.max to be 50, got %d", sc.C.Collectors.Forwarders.Max)
+	}
+	if sc.C.Collectors.Deployment.PhoneHomeThreshold != 15*time.Minute {
+		t.Errorf("Expected collectors.deployment.phone_home_threshold to be 15m, got %s", sc.C.Collectors.Deployment.PhoneHomeThreshold)
+	}
+	if sc.C.Collectors.Users.MaxUsers != 10 {
+		t.Errorf("Expected collectors.users.max_users to be 10, got %d", sc.C.Collectors.Users.MaxUsers)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"gopkg.in/yaml.v3"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	"github.com/K-Yo/splunk_exporter/exporter"
+)
+
+// discoveredMetric is a metric found in a Splunk metrics index
+type discoveredMetric struct {
+	Name        string
+	Dimensions  []string
+	Cardinality int
+}
+
+// discover lists metrics of an index on stdout, either as a table or as a configuration snippet
+func discover(logger log.Logger) int {
+	spk, err := exporter.NewSplunk(splunkOpts(sc.C), logger)
+	if err != nil {
+		level.Error(logger).Log("msg", "could not create Splunk client", "err", err)
+		return 1
+	}
+
+	names, err := spk.GetMetricNames(*discoverIndex, *discoverMatch)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to list metrics", "index", *discoverIndex, "match", *discoverMatch, "err", err)
+		return 1
+	}
+	level.Info(logger).Log("msg", "found metrics", "index", *discoverIndex, "match", *discoverMatch, "count", len(names))
+
+	if *discoverAsYAML {
+		snippet := struct {
+			Metrics []config.Metric `yaml:"metrics"`
+		}{}
+		for _, name := range names {
+			snippet.Metrics = append(snippet.Metrics, config.Metric{Index: *discoverIndex, Name: name})
+		}
+		out, err := yaml.Marshal(snippet)
+		if err != nil {
+			level.Error(logger).Log("msg", "failed to marshal metrics", "err", err)
+			return 1
+		}
+		os.Stdout.Write(out)
+		return 0
+	}
+
+	metrics := make([]discoveredMetric, 0, len(names))
+	for _, name := range names {
+		cardinality, err := spk.GetCardinality(*discoverIndex, name)
+		if err != nil {
+			level.Warn(logger).Log("msg", "failed to get metric cardinality", "metric_name", name, "err", err)
+			cardinality = -1
+		}
+		metrics = append(metrics, discoveredMetric{
+			Name:        name,
+			Dimensions:  spk.GetDimensions(*discoverIndex, name),
+			Cardinality: cardinality,
+		})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tCARDINALITY (24h)\tDIMENSIONS")
+	for _, m := range metrics {
+		cardinality := "?"
+		if m.Cardinality >= 0 {
+			cardinality = fmt.Sprint(m.Cardinality)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", m.Name, cardinality, strings.Join(m.Dimensions, ", "))
+	}
+	w.Flush()
+	return 0
+}
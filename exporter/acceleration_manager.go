@@ -0,0 +1,145 @@
+package exporter
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dataModelSummaryPrefix prefixes the title of data model summaries, it is followed by "<app>_<model>"
+const dataModelSummaryPrefix = "tstats:DM_"
+
+// AccelerationManager collects the state of data model and report acceleration summaries
+type AccelerationManager struct {
+	splunk               *splunklib.Splunk // Splunk client
+	logger               log.Logger
+	completionDescriptor *prometheus.Desc
+	sizeDescriptor       *prometheus.Desc
+	lagDescriptor        *prometheus.Desc
+	bucketsDescriptor    *prometheus.Desc
+}
+
+func newAccelerationManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *AccelerationManager {
+
+	level.Debug(logger).Log("msg", "Initiating acceleration manager")
+
+	labels := []string{"type", "app", "owner", "model"}
+	am := AccelerationManager{
+		splunk: spk,
+		logger: logger,
+		completionDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "acceleration", "completion_ratio"),
+			"Share of the summary range built, between 0 and 1, type is datamodel or report, from admin/summarization API",
+			labels, nil,
+		),
+		sizeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "acceleration", "size_bytes"),
+			"Size of the summary on disk, type is datamodel or report, from admin/summarization API",
+			labels, nil,
+		),
+		lagDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "acceleration", "update_lag_seconds"),
+			"Time since the summary was last updated, type is datamodel or report, from admin/summarization API",
+			labels, nil,
+		),
+		bucketsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "acceleration", "buckets"),
+			"Number of buckets of the summary, type is datamodel or report, from admin/summarization API",
+			labels, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating acceleration manager")
+	return &am
+}
+
+func (am *AccelerationManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(am.logger).Log("msg", "Collecting Acceleration measures")
+	ret := true
+	now := time.Now()
+
+	dataModels := make([]splunklib.Summarization, 0)
+	if err := am.splunk.ListAll(&dataModels, url.Values{"by_tstats": []string{"1"}}); err != nil {
+		level.Error(am.logger).Log("msg", "failed to list data model summaries", "err", err)
+		ret = false
+	} else {
+		am.collectDataModels(ch, dataModels, now)
+	}
+
+	reports := make([]splunklib.Summarization, 0)
+	if err := am.splunk.ListAll(&reports, nil); err != nil {
+		level.Error(am.logger).Log("msg", "failed to list report acceleration summaries", "err", err)
+		ret = false
+	} else {
+		am.collectReports(ch, reports, now)
+	}
+
+	level.Info(am.logger).Log("msg", "Done collecting Acceleration measures", "success", ret)
+	return ret
+}
+
+// collectDataModels sends the state of each data model summary
+func (am *AccelerationManager) collectDataModels(ch chan<- prometheus.Metric, summaries []splunklib.Summarization, now time.Time) {
+	for _, s := range summaries {
+		title := splunklib.EntryName(s.ID)
+		if !strings.HasPrefix(title, dataModelSummaryPrefix) {
+			continue
+		}
+		app := s.ACL.App
+		// app names may contain underscores, the app of the summary tells where the model name starts
+		model, ok := strings.CutPrefix(title, dataModelSummaryPrefix+app+"_")
+		if !ok {
+			model = strings.TrimPrefix(title, dataModelSummaryPrefix)
+		}
+		am.measureSummary(ch, &s.Content, now, "datamodel", app, s.ACL.Owner, model)
+	}
+}
+
+// collectReports sends the state of each report acceleration summary, once for each report sharing it
+// summaries without known report are identified by their ID.
+// A report listed by several summaries is only sent for the first one, its series would be duplicated.
+func (am *AccelerationManager) collectReports(ch chan<- prometheus.Metric, summaries []splunklib.Summarization, now time.Time) {
+	sent := make(map[splunklib.SummarizedReport]struct{})
+	for _, s := range summaries {
+		title := splunklib.EntryName(s.ID)
+		if strings.HasPrefix(title, dataModelSummaryPrefix) {
+			continue
+		}
+		reports := s.Content.SavedSearches
+		if len(reports) == 0 {
+			reports = []splunklib.SummarizedReport{{Owner: s.ACL.Owner, App: s.ACL.App, Name: title}}
+		}
+		for _, r := range reports {
+			if _, ok := sent[r]; ok {
+				level.Debug(am.logger).Log("msg", "Report listed by several summaries", "report", r.Name, "app", r.App, "owner", r.Owner, "summary", title)
+				continue
+			}
+			sent[r] = struct{}{}
+			am.measureSummary(ch, &s.Content, now, "report", r.App, r.Owner, r.Name)
+		}
+	}
+}
+
+// measureSummary sends completion, size, update lag and buckets of a summary
+// the update lag is not sent when the summary was never updated.
+func (am *AccelerationManager) measureSummary(ch chan<- prometheus.Metric, c *splunklib.SummarizationContent, now time.Time, labels ...string) {
+	ch <- prometheus.MustNewConstMetric(
+		am.completionDescriptor, prometheus.GaugeValue, float64(c.Complete), labels...,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		am.sizeDescriptor, prometheus.GaugeValue, float64(c.Size), labels...,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		am.bucketsDescriptor, prometheus.GaugeValue, float64(c.Buckets), labels...,
+	)
+	if c.ModTime > 0 {
+		ch <- prometheus.MustNewConstMetric(
+			am.lagDescriptor, prometheus.GaugeValue, now.Sub(time.Unix(int64(c.ModTime), 0)).Seconds(), labels...,
+		)
+	}
+}
@@ -0,0 +1,85 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccelerationManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/admin/summarization": "testdata/adminsummarization.json",
+	})
+	am := newAccelerationManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = am.CollectMeasures(ch)
+	})
+
+	// 2 data models and 3 reports, the report never updated has no lag
+	assert.Equal(t, 5*4-1, testutil.CollectAndCount(c))
+	assert.True(t, ok)
+}
+
+// same-named reports of different owners share no series
+func TestAccelerationManager_Summaries(t *testing.T) {
+	am := newAccelerationManager(namespace, nil, log.NewNopLogger())
+	summaries := readTestEntries[splunklib.Summarization](t, "testdata/adminsummarization.json")
+	now := time.Unix(1714641000, 0)
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		am.collectDataModels(ch, summaries, now)
+		am.collectReports(ch, summaries, now)
+	})
+
+	expected := `
+# HELP splunk_exporter_acceleration_completion_ratio Share of the summary range built, between 0 and 1, type is datamodel or report, from admin/summarization API
+# TYPE splunk_exporter_acceleration_completion_ratio gauge
+splunk_exporter_acceleration_completion_ratio{app="Splunk_SA_CIM",model="Authentication",owner="system",type="datamodel"} 1
+splunk_exporter_acceleration_completion_ratio{app="Splunk_SA_CIM",model="Network_Traffic",owner="system",type="datamodel"} 0.42
+splunk_exporter_acceleration_completion_ratio{app="search",model="0F1E2D3C-4B5A-6978-8796-A5B4C3D2E1F0",owner="system",type="report"} 0
+splunk_exporter_acceleration_completion_ratio{app="search",model="Errors by host",owner="admin",type="report"} 0.9
+splunk_exporter_acceleration_completion_ratio{app="search",model="Errors by host",owner="jdoe",type="report"} 0.5
+# HELP splunk_exporter_acceleration_update_lag_seconds Time since the summary was last updated, type is datamodel or report, from admin/summarization API
+# TYPE splunk_exporter_acceleration_update_lag_seconds gauge
+splunk_exporter_acceleration_update_lag_seconds{app="Splunk_SA_CIM",model="Authentication",owner="system",type="datamodel"} 600
+splunk_exporter_acceleration_update_lag_seconds{app="Splunk_SA_CIM",model="Network_Traffic",owner="system",type="datamodel"} 4200
+splunk_exporter_acceleration_update_lag_seconds{app="search",model="Errors by host",owner="admin",type="report"} 900
+splunk_exporter_acceleration_update_lag_seconds{app="search",model="Errors by host",owner="jdoe",type="report"} 1200
+# HELP splunk_exporter_acceleration_size_bytes Size of the summary on disk, type is datamodel or report, from admin/summarization API
+# TYPE splunk_exporter_acceleration_size_bytes gauge
+splunk_exporter_acceleration_size_bytes{app="Splunk_SA_CIM",model="Authentication",owner="system",type="datamodel"} 5.24288e+07
+splunk_exporter_acceleration_size_bytes{app="Splunk_SA_CIM",model="Network_Traffic",owner="system",type="datamodel"} 1.073741824e+09
+splunk_exporter_acceleration_size_bytes{app="search",model="0F1E2D3C-4B5A-6978-8796-A5B4C3D2E1F0",owner="system",type="report"} 0
+splunk_exporter_acceleration_size_bytes{app="search",model="Errors by host",owner="admin",type="report"} 1.048576e+06
+splunk_exporter_acceleration_size_bytes{app="search",model="Errors by host",owner="jdoe",type="report"} 524288
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_acceleration_completion_ratio",
+		"splunk_exporter_acceleration_update_lag_seconds",
+		"splunk_exporter_acceleration_size_bytes",
+	))
+}
+
+// a report listed by two summaries is sent once, gathering does not fail on duplicate series
+func TestAccelerationManager_DuplicateReports(t *testing.T) {
+	am := newAccelerationManager(namespace, nil, log.NewNopLogger())
+	summaries := readTestEntries[splunklib.Summarization](t, "testdata/adminsummarization.json")
+	summaries = append(summaries, summaries...)
+	now := time.Unix(1714641000, 0)
+
+	reg := prometheus.NewPedanticRegistry()
+	assert.NoError(t, reg.Register(testCollector(func(ch chan<- prometheus.Metric) {
+		am.collectReports(ch, summaries, now)
+	})))
+	count, err := testutil.GatherAndCount(reg, "splunk_exporter_acceleration_completion_ratio")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
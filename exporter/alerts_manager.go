@@ -0,0 +1,155 @@
+package exporter
+
+import (
+	"sync"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// allFiredAlerts is the title of the fired alerts entry summing all alerts
+const allFiredAlerts = "-"
+
+// alertSeverities are the names of the severities of a triggered alert
+var alertSeverities = []string{"info", "low", "medium", "high", "critical"}
+
+// alertSeverity maps the severity of a triggered alert to its name
+func alertSeverity(severity splunklib.Number) string {
+	switch severity {
+	case 1:
+		return "info"
+	case 2:
+		return "low"
+	case 3:
+		return "medium"
+	case 4:
+		return "high"
+	case 5:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// AlertsManager collects alerts fired by Splunk
+// Splunk only keeps triggered alerts until they expire, triggers are counted by the exporter from successive scrapes.
+// Counters of an alert start at 0 when it is first listed, only triggers listed afterwards are counted.
+type AlertsManager struct {
+	splunk              *splunklib.Splunk // Splunk client
+	logger              log.Logger
+	firedDescriptor     *prometheus.Desc
+	triggeredDescriptor *prometheus.Desc
+
+	// triggers are derived from successive scrapes
+	triggerMu sync.Mutex                     // guards seen and triggered
+	seen      map[string]map[string]struct{} // IDs of triggers listed during the last scrape, by alert
+	triggered map[string]map[string]float64  // triggers counted by alert and severity
+}
+
+func newAlertsManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *AlertsManager {
+
+	level.Debug(logger).Log("msg", "Initiating alerts manager")
+
+	am := AlertsManager{
+		splunk: spk,
+		logger: logger,
+		firedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "alert", "fired_alerts"),
+			"Number of triggered alerts Splunk keeps for an alert until they expire, from alerts/fired_alerts API",
+			[]string{"alert"}, nil,
+		),
+		triggeredDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "alert", "triggers_total"),
+			"Number of times an alert triggered since the exporter first listed it, by severity, from alerts/fired_alerts/{name} API",
+			[]string{"alert", "severity"}, nil,
+		),
+		seen:      make(map[string]map[string]struct{}),
+		triggered: make(map[string]map[string]float64),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating alerts manager")
+	return &am
+}
+
+func (am *AlertsManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(am.logger).Log("msg", "Collecting Alerts measures")
+
+	alerts := make([]splunklib.FiredAlert, 0)
+	if err := am.splunk.ListAll(&alerts, nil); err != nil {
+		level.Error(am.logger).Log("msg", "failed to list fired alerts", "err", err)
+		return false
+	}
+
+	ret := true
+	triggers := make(map[string][]splunklib.TriggeredAlert, len(alerts))
+	for _, a := range alerts {
+		name := splunklib.EntryName(a.ID)
+		if name == allFiredAlerts {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			am.firedDescriptor, prometheus.GaugeValue, float64(a.Content.TriggeredAlertCount), name,
+		)
+
+		t := make([]splunklib.TriggeredAlert, 0)
+		if err := am.splunk.ListAllIn(&t, name, nil); err != nil {
+			level.Error(am.logger).Log("msg", "failed to list triggered alerts", "alert", name, "err", err)
+			ret = false
+			continue
+		}
+		triggers[name] = t
+	}
+	am.collectTriggers(ch, triggers, ret)
+
+	level.Info(am.logger).Log("msg", "Done collecting Alerts measures", "success", ret)
+	return ret
+}
+
+// collectTriggers counts triggers not seen during previous scrapes, and sends the count of each alert
+// triggers holds the triggered alerts of each fired alert, complete tells whether all fired alerts were listed.
+// Alerts missing from an incomplete listing keep the triggers seen before, so they are not counted twice.
+func (am *AlertsManager) collectTriggers(ch chan<- prometheus.Metric, triggers map[string][]splunklib.TriggeredAlert, complete bool) {
+	am.triggerMu.Lock()
+	defer am.triggerMu.Unlock()
+
+	for alert, ts := range triggers {
+		_, known := am.triggered[alert]
+		if !known {
+			// new series start at 0, so that rate() and increase() see the first trigger counted
+			am.triggered[alert] = make(map[string]float64, len(alertSeverities))
+			for _, severity := range alertSeverities {
+				am.triggered[alert][severity] = 0
+			}
+		}
+		previous := am.seen[alert]
+		listed := make(map[string]struct{}, len(ts))
+		for _, t := range ts {
+			id := t.ID.Title
+			listed[id] = struct{}{}
+			if _, ok := previous[id]; ok || !known {
+				continue
+			}
+			am.triggered[alert][alertSeverity(t.Content.Severity)]++
+		}
+		// expired triggers are forgotten, they are not listed anymore
+		am.seen[alert] = listed
+	}
+	if complete {
+		for alert := range am.seen {
+			if _, ok := triggers[alert]; !ok {
+				delete(am.seen, alert)
+			}
+		}
+	}
+
+	// counters of alerts without triggered alerts left are kept, so they never decrease
+	for alert, counts := range am.triggered {
+		for severity, count := range counts {
+			ch <- prometheus.MustNewConstMetric(
+				am.triggeredDescriptor, prometheus.CounterValue, count, alert, severity,
+			)
+		}
+	}
+}
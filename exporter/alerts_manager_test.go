@@ -0,0 +1,88 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertsManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/alerts/fired_alerts":                 "testdata/alertsfiredalerts.json",
+		"/services/alerts/fired_alerts/Errors per hour": "testdata/alertsfiredalerts-errors.json",
+		"/services/alerts/fired_alerts/Disk full":       "testdata/alertsfiredalerts-disk.json",
+	})
+	am := newAlertsManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = am.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_alert_fired_alerts Number of triggered alerts Splunk keeps for an alert until they expire, from alerts/fired_alerts API
+# TYPE splunk_exporter_alert_fired_alerts gauge
+splunk_exporter_alert_fired_alerts{alert="Disk full"} 1
+splunk_exporter_alert_fired_alerts{alert="Errors per hour"} 3
+# HELP splunk_exporter_alert_triggers_total Number of times an alert triggered since the exporter first listed it, by severity, from alerts/fired_alerts/{name} API
+# TYPE splunk_exporter_alert_triggers_total counter
+splunk_exporter_alert_triggers_total{alert="Disk full",severity="critical"} 0
+splunk_exporter_alert_triggers_total{alert="Disk full",severity="high"} 0
+splunk_exporter_alert_triggers_total{alert="Disk full",severity="info"} 0
+splunk_exporter_alert_triggers_total{alert="Disk full",severity="low"} 0
+splunk_exporter_alert_triggers_total{alert="Disk full",severity="medium"} 0
+splunk_exporter_alert_triggers_total{alert="Errors per hour",severity="critical"} 0
+splunk_exporter_alert_triggers_total{alert="Errors per hour",severity="high"} 0
+splunk_exporter_alert_triggers_total{alert="Errors per hour",severity="info"} 0
+splunk_exporter_alert_triggers_total{alert="Errors per hour",severity="low"} 0
+splunk_exporter_alert_triggers_total{alert="Errors per hour",severity="medium"} 0
+`
+	// triggers listed when an alert is first seen are not counted, counters start at 0
+	// collecting twice does not count triggers again
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+			"splunk_exporter_alert_fired_alerts",
+			"splunk_exporter_alert_triggers_total",
+		))
+		assert.True(t, ok)
+	}
+}
+
+func TestAlertsManager_TriggerHistory(t *testing.T) {
+	am := newAlertsManager(namespace, nil, log.NewNopLogger())
+	triggers := readTestEntries[splunklib.TriggeredAlert](t, "testdata/alertsfiredalerts-errors.json")
+	oldest, medium, high := triggers[0], triggers[1], triggers[2]
+
+	collect := func(triggers map[string][]splunklib.TriggeredAlert, complete bool) {
+		c := testCollector(func(ch chan<- prometheus.Metric) {
+			am.collectTriggers(ch, triggers, complete)
+		})
+		testutil.CollectAndCount(c)
+	}
+
+	// the alert is first seen, its trigger happened before
+	collect(map[string][]splunklib.TriggeredAlert{"Errors per hour": {oldest}}, true)
+	collect(map[string][]splunklib.TriggeredAlert{"Errors per hour": {oldest, medium}}, true)
+	collect(map[string][]splunklib.TriggeredAlert{"Errors per hour": {medium, high}}, true)
+	// the medium trigger expired
+	collect(map[string][]splunklib.TriggeredAlert{"Errors per hour": {high}}, true)
+	// listing of the alert failed, the high trigger must not be counted again next time
+	collect(map[string][]splunklib.TriggeredAlert{}, false)
+	collect(map[string][]splunklib.TriggeredAlert{"Errors per hour": {high}}, true)
+	// all triggers expired, counters do not decrease
+	collect(map[string][]splunklib.TriggeredAlert{}, true)
+
+	assert.Equal(t, map[string]float64{"info": 0, "low": 0, "medium": 1, "high": 1, "critical": 0}, am.triggered["Errors per hour"])
+	assert.Empty(t, am.seen)
+}
+
+func TestAlertSeverity(t *testing.T) {
+	assert.Equal(t, "info", alertSeverity(1))
+	assert.Equal(t, "critical", alertSeverity(5))
+	assert.Equal(t, "unknown", alertSeverity(0))
+}
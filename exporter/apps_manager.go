@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"strconv"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// AppsManager collects the inventory of apps installed on the Splunk instance
+type AppsManager struct {
+	splunk                    *splunklib.Splunk // Splunk client
+	logger                    log.Logger
+	infoDescriptor            *prometheus.Desc
+	updateAvailableDescriptor *prometheus.Desc
+}
+
+func newAppsManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *AppsManager {
+
+	level.Debug(logger).Log("msg", "Initiating apps manager")
+
+	am := AppsManager{
+		splunk: spk,
+		logger: logger,
+		infoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "app", "info"),
+			"App installed on the Splunk instance, from apps/local API",
+			[]string{"app", "version", "enabled", "visible"}, nil,
+		),
+		updateAvailableDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "app", "update_available"),
+			"Whether a newer version of an app is available, update_version is empty when there is none, from apps/local API",
+			[]string{"app", "update_version"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating apps manager")
+	return &am
+}
+
+func (am *AppsManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(am.logger).Log("msg", "Collecting Apps measures")
+
+	apps := make([]splunklib.AppLocal, 0)
+	if err := am.splunk.ListAll(&apps, nil); err != nil {
+		level.Error(am.logger).Log("msg", "failed to list apps", "err", err)
+		return false
+	}
+	am.collectApps(ch, apps)
+
+	level.Info(am.logger).Log("msg", "Done collecting Apps measures")
+	return true
+}
+
+// collectApps sends version and state of each app, and whether it can be updated
+func (am *AppsManager) collectApps(ch chan<- prometheus.Metric, apps []splunklib.AppLocal) {
+	for _, a := range apps {
+		c := a.Content
+		ch <- prometheus.MustNewConstMetric(
+			am.infoDescriptor, prometheus.GaugeValue, 1,
+			a.ID.Title, c.Version, strconv.FormatBool(!bool(c.Disabled)), strconv.FormatBool(bool(c.Visible)),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			am.updateAvailableDescriptor, prometheus.GaugeValue, boolToFloat(c.UpdateVersion != ""), a.ID.Title, c.UpdateVersion,
+		)
+	}
+}
@@ -0,0 +1,41 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppsManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/apps/local": "testdata/appslocal.json",
+	})
+	am := newAppsManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = am.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_app_info App installed on the Splunk instance, from apps/local API
+# TYPE splunk_exporter_app_info gauge
+splunk_exporter_app_info{app="Splunk_SA_CIM",enabled="true",version="5.2.0",visible="false"} 1
+splunk_exporter_app_info{app="search",enabled="true",version="9.2.1",visible="true"} 1
+splunk_exporter_app_info{app="splunk_secure_gateway",enabled="false",version="3.5.15",visible="true"} 1
+# HELP splunk_exporter_app_update_available Whether a newer version of an app is available, update_version is empty when there is none, from apps/local API
+# TYPE splunk_exporter_app_update_available gauge
+splunk_exporter_app_update_available{app="Splunk_SA_CIM",update_version="5.3.1"} 1
+splunk_exporter_app_update_available{app="search",update_version=""} 0
+splunk_exporter_app_update_available{app="splunk_secure_gateway",update_version=""} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_app_info",
+		"splunk_exporter_app_update_available",
+	))
+	assert.True(t, ok)
+}
@@ -0,0 +1,223 @@
+package exporter
+
+import (
+	"net/url"
+	"slices"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// fixupLevels are the levels buckets can need fixup at, see cluster/manager/fixup documentation
+	fixupLevels = []string{"streaming", "data_safety", "generation", "replication_factor", "search_factor", "checksum_sync"}
+
+	// peerStatuses are the known statuses of an indexer cluster peer
+	peerStatuses = []string{"Up", "Pending", "AutomaticDetention", "ManualDetention", "ManualDetention-PortsEnabled", "Restarting", "ShuttingDown", "ReassigningPrimaries", "Decommissioning", "GracefulShutdown", "Stopped", "Down", "BatchAdding"}
+)
+
+// ClusterManager collects indexer clustering measures from a cluster manager
+type ClusterManager struct {
+	splunk                      *splunklib.Splunk // Splunk client
+	logger                      log.Logger
+	maintenanceModeDescriptor   *prometheus.Desc
+	rollingRestartDescriptor    *prometheus.Desc
+	serviceReadyDescriptor      *prometheus.Desc
+	replicationFactorDescriptor *prometheus.Desc
+	searchFactorDescriptor      *prometheus.Desc
+	generationDescriptor        *prometheus.Desc
+	fixupDescriptor             *prometheus.Desc
+	peerUpDescriptor            *prometheus.Desc
+	peerStatusDescriptor        *prometheus.Desc
+	peerSearchableDescriptor    *prometheus.Desc
+	peerBucketsDescriptor       *prometheus.Desc
+	peerPrimariesDescriptor     *prometheus.Desc
+}
+
+func newClusterManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *ClusterManager {
+
+	level.Debug(logger).Log("msg", "Initiating cluster manager")
+
+	cm := ClusterManager{
+		splunk: spk,
+		logger: logger,
+		maintenanceModeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "maintenance_mode"),
+			"Whether the indexer cluster is in maintenance mode, from cluster/manager/info API",
+			nil, nil,
+		),
+		rollingRestartDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "rolling_restart"),
+			"Whether the indexer cluster is in a rolling restart, from cluster/manager/info API",
+			nil, nil,
+		),
+		serviceReadyDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "service_ready"),
+			"Whether the cluster manager is ready to provide service, from cluster/manager/info API",
+			nil, nil,
+		),
+		replicationFactorDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "replication_factor_met"),
+			"Whether the indexer cluster meets its replication factor, from cluster/manager/generation API",
+			nil, nil,
+		),
+		searchFactorDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "search_factor_met"),
+			"Whether the indexer cluster meets its search factor, from cluster/manager/generation API",
+			nil, nil,
+		),
+		generationDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "generation_id"),
+			"Current generation of the indexer cluster, from cluster/manager/generation API",
+			nil, nil,
+		),
+		fixupDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "fixup_buckets"),
+			"Number of buckets pending fixup, from cluster/manager/fixup API",
+			[]string{"level"}, nil,
+		),
+		peerUpDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "peer_up"),
+			"Whether an indexer cluster peer has the Up status, from cluster/manager/peers API",
+			[]string{"peer", "site"}, nil,
+		),
+		peerStatusDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "peer_status"),
+			"Status of an indexer cluster peer, 1 for its current status, from cluster/manager/peers API",
+			[]string{"peer", "status"}, nil,
+		),
+		peerSearchableDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "peer_searchable"),
+			"Whether an indexer cluster peer is searchable, from cluster/manager/peers API",
+			[]string{"peer"}, nil,
+		),
+		peerBucketsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "peer_buckets"),
+			"Number of buckets on an indexer cluster peer, from cluster/manager/peers API",
+			[]string{"peer"}, nil,
+		),
+		peerPrimariesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "peer_primary_buckets"),
+			"Number of primary buckets on an indexer cluster peer, from cluster/manager/peers API",
+			[]string{"peer"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating cluster manager")
+	return &cm
+}
+
+// CollectMeasures collects indexer clustering measures, it does nothing when the instance is not a cluster manager
+func (cm *ClusterManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	isManager, err := hasServerRole(cm.splunk, clusterManagerRoles...)
+	if err != nil {
+		level.Error(cm.logger).Log("msg", "failed to read server roles", "err", err)
+		return false
+	}
+	if !isManager {
+		level.Debug(cm.logger).Log("msg", "Instance is not a cluster manager, skipping Cluster measures")
+		return true
+	}
+
+	level.Info(cm.logger).Log("msg", "Collecting Cluster measures")
+	ret := true
+
+	info := splunklib.ClusterManagerInfo{}
+	if err := cm.splunk.Client.Read(&info); err != nil {
+		level.Error(cm.logger).Log("msg", "failed to read cluster manager info", "err", err)
+		ret = false
+	} else {
+		cm.collectInfo(ch, &info)
+	}
+
+	generation := splunklib.ClusterManagerGeneration{}
+	if err := cm.splunk.Client.Read(&generation); err != nil {
+		level.Error(cm.logger).Log("msg", "failed to read cluster generation", "err", err)
+		ret = false
+	} else {
+		cm.collectGeneration(ch, &generation)
+	}
+
+	peers := make([]splunklib.ClusterManagerPeer, 0)
+	if err := cm.splunk.ListAll(&peers, nil); err != nil {
+		level.Error(cm.logger).Log("msg", "failed to list cluster peers", "err", err)
+		ret = false
+	} else {
+		cm.collectPeers(ch, peers)
+	}
+
+	for _, l := range fixupLevels {
+		count, err := cm.splunk.CountEntries(splunklib.ClusterManagerFixup{}, url.Values{"level": []string{l}})
+		if err != nil {
+			level.Error(cm.logger).Log("msg", "failed to count buckets pending fixup", "level", l, "err", err)
+			ret = false
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			cm.fixupDescriptor, prometheus.GaugeValue, float64(count), l,
+		)
+	}
+
+	level.Info(cm.logger).Log("msg", "Done collecting Cluster measures", "success", ret)
+	return ret
+}
+
+// collectInfo sends cluster wide flags
+func (cm *ClusterManager) collectInfo(ch chan<- prometheus.Metric, info *splunklib.ClusterManagerInfo) {
+	ch <- prometheus.MustNewConstMetric(
+		cm.maintenanceModeDescriptor, prometheus.GaugeValue, boolToFloat(bool(info.Content.MaintenanceMode)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		cm.rollingRestartDescriptor, prometheus.GaugeValue, boolToFloat(bool(info.Content.RollingRestartFlag)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		cm.serviceReadyDescriptor, prometheus.GaugeValue, boolToFloat(bool(info.Content.ServiceReadyFlag)),
+	)
+}
+
+// collectGeneration sends replication and search factors status
+func (cm *ClusterManager) collectGeneration(ch chan<- prometheus.Metric, generation *splunklib.ClusterManagerGeneration) {
+	ch <- prometheus.MustNewConstMetric(
+		cm.replicationFactorDescriptor, prometheus.GaugeValue, boolToFloat(bool(generation.Content.ReplicationFactorMet)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		cm.searchFactorDescriptor, prometheus.GaugeValue, boolToFloat(bool(generation.Content.SearchFactorMet)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		cm.generationDescriptor, prometheus.GaugeValue, float64(generation.Content.GenerationID),
+	)
+}
+
+// collectPeers sends status and bucket counts of each peer
+func (cm *ClusterManager) collectPeers(ch chan<- prometheus.Metric, peers []splunklib.ClusterManagerPeer) {
+	for _, p := range peers {
+		name := p.Content.Label
+		if name == "" {
+			name = p.ID.Title
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			cm.peerUpDescriptor, prometheus.GaugeValue, boolToFloat(p.Content.Status == "Up"), name, p.Content.Site,
+		)
+		statuses := peerStatuses
+		if !slices.Contains(statuses, p.Content.Status) {
+			statuses = append(slices.Clone(statuses), p.Content.Status)
+		}
+		for _, s := range statuses {
+			ch <- prometheus.MustNewConstMetric(
+				cm.peerStatusDescriptor, prometheus.GaugeValue, boolToFloat(p.Content.Status == s), name, s,
+			)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			cm.peerSearchableDescriptor, prometheus.GaugeValue, boolToFloat(bool(p.Content.IsSearchable)), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			cm.peerBucketsDescriptor, prometheus.GaugeValue, float64(p.Content.BucketCount), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			cm.peerPrimariesDescriptor, prometheus.GaugeValue, float64(p.Content.PrimaryCount), name,
+		)
+	}
+}
@@ -0,0 +1,110 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info":                                    "testdata/serverinfo.json",
+		"/services/cluster/manager/info":                           "testdata/clustermanagerinfo.json",
+		"/services/cluster/manager/generation":                     "testdata/clustermanagergeneration.json",
+		"/services/cluster/manager/peers":                          "testdata/clustermanagerpeers.json",
+		"/services/cluster/manager/fixup?level=streaming":          "testdata/clustermanagerfixup-streaming.json",
+		"/services/cluster/manager/fixup?level=data_safety":        "testdata/clustermanagerfixup-data_safety.json",
+		"/services/cluster/manager/fixup?level=generation":         "testdata/clustermanagerfixup-generation.json",
+		"/services/cluster/manager/fixup?level=replication_factor": "testdata/clustermanagerfixup-replication_factor.json",
+		"/services/cluster/manager/fixup?level=search_factor":      "testdata/clustermanagerfixup-search_factor.json",
+		"/services/cluster/manager/fixup?level=checksum_sync":      "testdata/clustermanagerfixup-checksum_sync.json",
+	})
+	cm := newClusterManager(namespace, spk, log.NewNopLogger())
+
+	var ret bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ret = cm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_cluster_fixup_buckets Number of buckets pending fixup, from cluster/manager/fixup API
+# TYPE splunk_exporter_cluster_fixup_buckets gauge
+splunk_exporter_cluster_fixup_buckets{level="checksum_sync"} 3
+splunk_exporter_cluster_fixup_buckets{level="data_safety"} 0
+splunk_exporter_cluster_fixup_buckets{level="generation"} 1
+splunk_exporter_cluster_fixup_buckets{level="replication_factor"} 5
+splunk_exporter_cluster_fixup_buckets{level="search_factor"} 7
+splunk_exporter_cluster_fixup_buckets{level="streaming"} 2
+# HELP splunk_exporter_cluster_generation_id Current generation of the indexer cluster, from cluster/manager/generation API
+# TYPE splunk_exporter_cluster_generation_id gauge
+splunk_exporter_cluster_generation_id 42
+# HELP splunk_exporter_cluster_maintenance_mode Whether the indexer cluster is in maintenance mode, from cluster/manager/info API
+# TYPE splunk_exporter_cluster_maintenance_mode gauge
+splunk_exporter_cluster_maintenance_mode 1
+# HELP splunk_exporter_cluster_peer_buckets Number of buckets on an indexer cluster peer, from cluster/manager/peers API
+# TYPE splunk_exporter_cluster_peer_buckets gauge
+splunk_exporter_cluster_peer_buckets{peer="idx1"} 1200
+splunk_exporter_cluster_peer_buckets{peer="idx2"} 1180
+splunk_exporter_cluster_peer_buckets{peer="idx3"} 1100
+# HELP splunk_exporter_cluster_peer_primary_buckets Number of primary buckets on an indexer cluster peer, from cluster/manager/peers API
+# TYPE splunk_exporter_cluster_peer_primary_buckets gauge
+splunk_exporter_cluster_peer_primary_buckets{peer="idx1"} 600
+splunk_exporter_cluster_peer_primary_buckets{peer="idx2"} 580
+splunk_exporter_cluster_peer_primary_buckets{peer="idx3"} 0
+# HELP splunk_exporter_cluster_peer_searchable Whether an indexer cluster peer is searchable, from cluster/manager/peers API
+# TYPE splunk_exporter_cluster_peer_searchable gauge
+splunk_exporter_cluster_peer_searchable{peer="idx1"} 1
+splunk_exporter_cluster_peer_searchable{peer="idx2"} 1
+splunk_exporter_cluster_peer_searchable{peer="idx3"} 0
+# HELP splunk_exporter_cluster_peer_up Whether an indexer cluster peer has the Up status, from cluster/manager/peers API
+# TYPE splunk_exporter_cluster_peer_up gauge
+splunk_exporter_cluster_peer_up{peer="idx1",site="site1"} 1
+splunk_exporter_cluster_peer_up{peer="idx2",site="site2"} 1
+splunk_exporter_cluster_peer_up{peer="idx3",site="site2"} 0
+# HELP splunk_exporter_cluster_replication_factor_met Whether the indexer cluster meets its replication factor, from cluster/manager/generation API
+# TYPE splunk_exporter_cluster_replication_factor_met gauge
+splunk_exporter_cluster_replication_factor_met 1
+# HELP splunk_exporter_cluster_rolling_restart Whether the indexer cluster is in a rolling restart, from cluster/manager/info API
+# TYPE splunk_exporter_cluster_rolling_restart gauge
+splunk_exporter_cluster_rolling_restart 0
+# HELP splunk_exporter_cluster_search_factor_met Whether the indexer cluster meets its search factor, from cluster/manager/generation API
+# TYPE splunk_exporter_cluster_search_factor_met gauge
+splunk_exporter_cluster_search_factor_met 0
+# HELP splunk_exporter_cluster_service_ready Whether the cluster manager is ready to provide service, from cluster/manager/info API
+# TYPE splunk_exporter_cluster_service_ready gauge
+splunk_exporter_cluster_service_ready 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_cluster_fixup_buckets",
+		"splunk_exporter_cluster_generation_id",
+		"splunk_exporter_cluster_maintenance_mode",
+		"splunk_exporter_cluster_peer_buckets",
+		"splunk_exporter_cluster_peer_primary_buckets",
+		"splunk_exporter_cluster_peer_searchable",
+		"splunk_exporter_cluster_peer_up",
+		"splunk_exporter_cluster_replication_factor_met",
+		"splunk_exporter_cluster_rolling_restart",
+		"splunk_exporter_cluster_search_factor_met",
+		"splunk_exporter_cluster_service_ready",
+	))
+	assert.True(t, ret)
+
+	// one series per known status for each peer
+	assert.Equal(t, 3*len(peerStatuses), testutil.CollectAndCount(c, "splunk_exporter_cluster_peer_status"))
+}
+
+// a search head or an indexer must not report cluster manager measures, nor fail
+func TestClusterManager_NotAManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info": "testdata/serverinfo-indexer.json",
+	})
+	cm := newClusterManager(namespace, spk, log.NewNopLogger())
+
+	ch := make(chan prometheus.Metric, 100)
+	assert.True(t, cm.CollectMeasures(ch))
+	assert.Empty(t, ch)
+}
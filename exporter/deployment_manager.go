@@ -0,0 +1,158 @@
+package exporter
+
+import (
+	"time"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultPhoneHomeThreshold is the time after which a deployment client not phoning home is counted as missing, when not configured
+const defaultPhoneHomeThreshold = time.Hour
+
+// deploymentResultOk is the result of a successful app deployment on a client
+const deploymentResultOk = "Ok"
+
+// DeploymentManager collects deployment server clients, skipped on instances that are not deployment servers
+type DeploymentManager struct {
+	splunk                  *splunklib.Splunk // Splunk client
+	logger                  log.Logger
+	threshold               time.Duration // clients not phoned home for longer are missing
+	clientsDescriptor       *prometheus.Desc
+	classClientsDescriptor  *prometheus.Desc
+	missingDescriptor       *prometheus.Desc
+	failuresDescriptor      *prometheus.Desc
+	failedClientsDescriptor *prometheus.Desc
+}
+
+func newDeploymentManager(namespace string, spk *splunklib.Splunk, logger log.Logger, conf config.Deployment) *DeploymentManager {
+
+	level.Debug(logger).Log("msg", "Initiating deployment manager")
+
+	threshold := conf.PhoneHomeThreshold
+	if threshold <= 0 {
+		threshold = defaultPhoneHomeThreshold
+	}
+
+	dm := DeploymentManager{
+		splunk:    spk,
+		logger:    logger,
+		threshold: threshold,
+		clientsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "deployment", "clients"),
+			"Number of deployment clients known by the deployment server, from deployment/server/clients API",
+			nil, nil,
+		),
+		classClientsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "deployment", "serverclass_clients"),
+			"Number of deployment clients in a server class, from deployment/server/clients API",
+			[]string{"serverclass"}, nil,
+		),
+		missingDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "deployment", "clients_not_phoned_home"),
+			"Number of deployment clients that did not phone home within the configured threshold, from deployment/server/clients API",
+			nil, nil,
+		),
+		failuresDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "deployment", "app_failures"),
+			"Number of deployment clients where the last deployment of an app failed, from deployment/server/clients API",
+			[]string{"app"}, nil,
+		),
+		failedClientsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "deployment", "failed_clients"),
+			"Number of deployment clients with at least one failed app deployment, from deployment/server/clients API",
+			nil, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating deployment manager")
+	return &dm
+}
+
+func (dm *DeploymentManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	isServer, err := hasServerRole(dm.splunk, deploymentServerRoles...)
+	if err != nil {
+		level.Error(dm.logger).Log("msg", "failed to read server roles", "err", err)
+		return false
+	}
+	if !isServer {
+		level.Debug(dm.logger).Log("msg", "Instance is not a deployment server, skipping Deployment measures")
+		return true
+	}
+
+	level.Info(dm.logger).Log("msg", "Collecting Deployment measures")
+	ret := true
+
+	classes := make([]splunklib.DeploymentServerClass, 0)
+	if err := dm.splunk.ListAll(&classes, nil); err != nil {
+		level.Error(dm.logger).Log("msg", "failed to list server classes", "err", err)
+		ret = false
+	}
+
+	clients := make([]splunklib.DeploymentServerClient, 0)
+	if err := dm.splunk.ListAll(&clients, nil); err != nil {
+		level.Error(dm.logger).Log("msg", "failed to list deployment clients", "err", err)
+		ret = false
+	} else {
+		dm.collectClients(ch, classes, clients, time.Now())
+	}
+
+	level.Info(dm.logger).Log("msg", "Done collecting Deployment measures", "success", ret)
+	return ret
+}
+
+// collectClients counts clients by server class, clients missing since threshold, and failed deployments
+// server classes without clients are sent with a zero count, now is used to compute the time since last phone home.
+func (dm *DeploymentManager) collectClients(ch chan<- prometheus.Metric, classes []splunklib.DeploymentServerClass, clients []splunklib.DeploymentServerClient, now time.Time) {
+	classClients := make(map[string]float64, len(classes))
+	for _, c := range classes {
+		classClients[c.ID.Title] = 0
+	}
+	failures := make(map[string]float64)
+	var missing, failed float64
+
+	for _, c := range clients {
+		for class := range c.Content.ServerClasses {
+			classClients[class]++
+		}
+
+		lastPhoneHome := time.Unix(int64(c.Content.LastPhoneHomeTime), 0)
+		if now.Sub(lastPhoneHome) > dm.threshold {
+			missing++
+		}
+
+		hasFailed := false
+		for app, a := range c.Content.Applications {
+			if a.Result != "" && a.Result != deploymentResultOk {
+				failures[app]++
+				hasFailed = true
+			}
+		}
+		if hasFailed {
+			failed++
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		dm.clientsDescriptor, prometheus.GaugeValue, float64(len(clients)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		dm.missingDescriptor, prometheus.GaugeValue, missing,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		dm.failedClientsDescriptor, prometheus.GaugeValue, failed,
+	)
+	for class, count := range classClients {
+		ch <- prometheus.MustNewConstMetric(
+			dm.classClientsDescriptor, prometheus.GaugeValue, count, class,
+		)
+	}
+	for app, count := range failures {
+		ch <- prometheus.MustNewConstMetric(
+			dm.failuresDescriptor, prometheus.GaugeValue, count, app,
+		)
+	}
+}
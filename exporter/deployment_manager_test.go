@@ -0,0 +1,93 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentClients(t *testing.T) {
+	dm := newDeploymentManager(namespace, nil, log.NewNopLogger(), config.Deployment{})
+	classes := readTestEntries[splunklib.DeploymentServerClass](t, "testdata/deploymentserverclasses.json")
+	clients := readTestEntries[splunklib.DeploymentServerClient](t, "testdata/deploymentserverclients.json")
+	now := time.Unix(1714640400, 0)
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		dm.collectClients(ch, classes, clients, now)
+	})
+
+	expected := `
+# HELP splunk_exporter_deployment_app_failures Number of deployment clients where the last deployment of an app failed, from deployment/server/clients API
+# TYPE splunk_exporter_deployment_app_failures gauge
+splunk_exporter_deployment_app_failures{app="Splunk_TA_nix"} 1
+splunk_exporter_deployment_app_failures{app="Splunk_TA_windows"} 1
+splunk_exporter_deployment_app_failures{app="outputs"} 1
+# HELP splunk_exporter_deployment_clients Number of deployment clients known by the deployment server, from deployment/server/clients API
+# TYPE splunk_exporter_deployment_clients gauge
+splunk_exporter_deployment_clients 4
+# HELP splunk_exporter_deployment_clients_not_phoned_home Number of deployment clients that did not phone home within the configured threshold, from deployment/server/clients API
+# TYPE splunk_exporter_deployment_clients_not_phoned_home gauge
+splunk_exporter_deployment_clients_not_phoned_home 1
+# HELP splunk_exporter_deployment_failed_clients Number of deployment clients with at least one failed app deployment, from deployment/server/clients API
+# TYPE splunk_exporter_deployment_failed_clients gauge
+splunk_exporter_deployment_failed_clients 2
+# HELP splunk_exporter_deployment_serverclass_clients Number of deployment clients in a server class, from deployment/server/clients API
+# TYPE splunk_exporter_deployment_serverclass_clients gauge
+splunk_exporter_deployment_serverclass_clients{serverclass="all_forwarders"} 4
+splunk_exporter_deployment_serverclass_clients{serverclass="linux"} 2
+splunk_exporter_deployment_serverclass_clients{serverclass="unused"} 0
+splunk_exporter_deployment_serverclass_clients{serverclass="windows"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestDeploymentClients_Threshold(t *testing.T) {
+	dm := newDeploymentManager(namespace, nil, log.NewNopLogger(), config.Deployment{PhoneHomeThreshold: 40 * time.Second})
+	clients := readTestEntries[splunklib.DeploymentServerClient](t, "testdata/deploymentserverclients.json")
+	now := time.Unix(1714640400, 0)
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		dm.collectClients(ch, nil, clients, now)
+	})
+
+	expected := `
+# HELP splunk_exporter_deployment_clients_not_phoned_home Number of deployment clients that did not phone home within the configured threshold, from deployment/server/clients API
+# TYPE splunk_exporter_deployment_clients_not_phoned_home gauge
+splunk_exporter_deployment_clients_not_phoned_home 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "splunk_exporter_deployment_clients_not_phoned_home"))
+}
+
+func TestDeploymentManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info":                     "testdata/serverinfo-ds.json",
+		"/services/deployment/server/clients":       "testdata/deploymentserverclients.json",
+		"/services/deployment/server/serverclasses": "testdata/deploymentserverclasses.json",
+	})
+	dm := newDeploymentManager(namespace, spk, log.NewNopLogger(), config.Deployment{})
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = dm.CollectMeasures(ch)
+	})
+	assert.Equal(t, 4, testutil.CollectAndCount(c, "splunk_exporter_deployment_serverclass_clients"))
+	assert.True(t, ok)
+}
+
+func TestDeploymentManager_NotADeploymentServer(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info": "testdata/serverinfo-indexer.json",
+	})
+	dm := newDeploymentManager(namespace, spk, log.NewNopLogger(), config.Deployment{})
+
+	ch := make(chan prometheus.Metric, 100)
+	assert.True(t, dm.CollectMeasures(ch))
+	assert.Empty(t, ch)
+}
@@ -0,0 +1,118 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DiskManager collects space of partitions used by Splunk, and the free space under which indexing pauses
+type DiskManager struct {
+	splunk             *splunklib.Splunk // Splunk client
+	logger             log.Logger
+	capacityDescriptor *prometheus.Desc
+	freeDescriptor     *prometheus.Desc
+	minFreeDescriptor  *prometheus.Desc
+}
+
+func newDiskManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *DiskManager {
+
+	level.Debug(logger).Log("msg", "Initiating disk manager")
+
+	dm := DiskManager{
+		splunk: spk,
+		logger: logger,
+		capacityDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "disk", "capacity_bytes"),
+			"Capacity of a partition used by Splunk, from server/status/partitions-space API",
+			[]string{"mount_point", "fs_type"}, nil,
+		),
+		freeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "disk", "free_bytes"),
+			"Free space of a partition used by Splunk, from server/status/partitions-space API",
+			[]string{"mount_point", "fs_type"}, nil,
+		),
+		minFreeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "disk", "min_free_bytes"),
+			"Free space under which Splunk pauses indexing and searches on a partition (minFreeSpace), from server/settings API",
+			[]string{"mount_point"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating disk manager")
+	return &dm
+}
+
+func (dm *DiskManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(dm.logger).Log("msg", "Collecting Disk measures")
+	ret := true
+
+	settings := splunklib.ServerSettings{}
+	if err := dm.splunk.Client.Read(&settings); err != nil {
+		level.Error(dm.logger).Log("msg", "failed to read server settings", "err", err)
+		ret = false
+	}
+
+	partitions := make([]splunklib.ServerPartitionSpace, 0)
+	if err := dm.splunk.ListAll(&partitions, nil); err != nil {
+		level.Error(dm.logger).Log("msg", "failed to list partitions", "err", err)
+		ret = false
+	} else if err := dm.collectPartitions(ch, partitions, settings.Content.MinFreeSpace); err != nil {
+		level.Error(dm.logger).Log("msg", "failed to compute minimum free space", "err", err)
+		ret = false
+	}
+
+	level.Info(dm.logger).Log("msg", "Done collecting Disk measures", "success", ret)
+	return ret
+}
+
+// collectPartitions sends capacity, free space and minimum free space of each partition
+// minimum free space is not sent when minFreeSpace is empty or invalid, an error is returned in the latter case.
+func (dm *DiskManager) collectPartitions(ch chan<- prometheus.Metric, partitions []splunklib.ServerPartitionSpace, minFreeSpace string) error {
+	var minFreeErr error
+	for _, p := range partitions {
+		c := p.Content
+		capacity := float64(c.Capacity) * bytesPerMB
+		ch <- prometheus.MustNewConstMetric(
+			dm.capacityDescriptor, prometheus.GaugeValue, capacity, c.MountPoint, c.FSType,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			dm.freeDescriptor, prometheus.GaugeValue, float64(c.Free)*bytesPerMB, c.MountPoint, c.FSType,
+		)
+
+		if minFreeSpace == "" {
+			continue
+		}
+		minFree, err := minFreeBytes(minFreeSpace, capacity)
+		if err != nil {
+			minFreeErr = err
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			dm.minFreeDescriptor, prometheus.GaugeValue, minFree, c.MountPoint,
+		)
+	}
+	return minFreeErr
+}
+
+// minFreeBytes converts a minFreeSpace setting to bytes, for a partition of the given capacity in bytes
+// the setting is either in MB, or a percentage of the capacity like "5%".
+func minFreeBytes(setting string, capacity float64) (float64, error) {
+	if pct, ok := strings.CutSuffix(setting, "%"); ok {
+		v, err := strconv.ParseFloat(pct, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid minFreeSpace %q: %w", setting, err)
+		}
+		return capacity * v / 100, nil
+	}
+	v, err := strconv.ParseFloat(setting, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid minFreeSpace %q: %w", setting, err)
+	}
+	return v * bytesPerMB, nil
+}
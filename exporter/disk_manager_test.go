@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/settings":                "testdata/serversettings.json",
+		"/services/server/status/partitions-space": "testdata/serverpartitionsspace.json",
+	})
+	dm := newDiskManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = dm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_disk_capacity_bytes Capacity of a partition used by Splunk, from server/status/partitions-space API
+# TYPE splunk_exporter_disk_capacity_bytes gauge
+splunk_exporter_disk_capacity_bytes{fs_type="ext4",mount_point="/opt/splunk"} 1.073741824e+11
+splunk_exporter_disk_capacity_bytes{fs_type="xfs",mount_point="/data/hot"} 2.147483648e+12
+# HELP splunk_exporter_disk_free_bytes Free space of a partition used by Splunk, from server/status/partitions-space API
+# TYPE splunk_exporter_disk_free_bytes gauge
+splunk_exporter_disk_free_bytes{fs_type="ext4",mount_point="/opt/splunk"} 5.36870912e+10
+splunk_exporter_disk_free_bytes{fs_type="xfs",mount_point="/data/hot"} 1.073741824e+10
+# HELP splunk_exporter_disk_min_free_bytes Free space under which Splunk pauses indexing and searches on a partition (minFreeSpace), from server/settings API
+# TYPE splunk_exporter_disk_min_free_bytes gauge
+splunk_exporter_disk_min_free_bytes{mount_point="/data/hot"} 5.24288e+09
+splunk_exporter_disk_min_free_bytes{mount_point="/opt/splunk"} 5.24288e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+	assert.True(t, ok)
+}
+
+func TestDiskPartitions_MinFreePercentage(t *testing.T) {
+	dm := newDiskManager(namespace, nil, log.NewNopLogger())
+	partitions := readTestEntries[splunklib.ServerPartitionSpace](t, "testdata/serverpartitionsspace.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		assert.NoError(t, dm.collectPartitions(ch, partitions, "5%"))
+	})
+
+	expected := `
+# HELP splunk_exporter_disk_min_free_bytes Free space under which Splunk pauses indexing and searches on a partition (minFreeSpace), from server/settings API
+# TYPE splunk_exporter_disk_min_free_bytes gauge
+splunk_exporter_disk_min_free_bytes{mount_point="/data/hot"} 1.073741824e+11
+splunk_exporter_disk_min_free_bytes{mount_point="/opt/splunk"} 5.36870912e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "splunk_exporter_disk_min_free_bytes"))
+}
+
+func TestDiskPartitions_InvalidMinFree(t *testing.T) {
+	dm := newDiskManager(namespace, nil, log.NewNopLogger())
+	partitions := readTestEntries[splunklib.ServerPartitionSpace](t, "testdata/serverpartitionsspace.json")
+
+	ch := make(chan prometheus.Metric, 100)
+	assert.Error(t, dm.collectPartitions(ch, partitions, "lots"))
+	// capacity and free space are still sent
+	assert.Len(t, ch, 4)
+}
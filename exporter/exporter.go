@@ -3,9 +3,11 @@ package exporter
 import (
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/K-Yo/splunk_exporter/config"
 	splunklib "github.com/K-Yo/splunk_exporter/splunk"
@@ -26,13 +28,106 @@ var (
 		"Was the last query of Splunk successful.",
 		nil, nil,
 	)
+	collectorSuccess = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "collector", "success"),
+		"Whether a collector succeeded during the last scrape.",
+		[]string{"collector"}, nil,
+	)
+	collectorDuration = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "collector", "duration_seconds"),
+		"Duration of a collector during the last scrape.",
+		[]string{"collector"}, nil,
+	)
 	indexer_throughput = prometheus.NewDesc(
 		prometheus.BuildFQName(namespace, "indexer", "throughput_bytes_per_seconds_average"),
 		"Average throughput processed by instance indexer, from server/introspection/indexer endpoint",
 		nil, nil,
 	)
+	indexer_status = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "indexer", "status"),
+		"Status of instance indexer, 1 for the current one, from server/introspection/indexer endpoint",
+		[]string{"status", "reason"}, nil,
+	)
+
+	index_size = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "size_bytes"),
+		"Size of an index on disk, from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_max_size = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "max_size_bytes"),
+		"Maximum size of an index (maxTotalDataSizeMB), from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_size_utilization = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "size_utilization_ratio"),
+		"Size of an index divided by its maximum size, from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_events = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "events"),
+		"Number of events in an index, from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_retention = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "retention_seconds"),
+		"Age after which events of an index are frozen (frozenTimePeriodInSecs), from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_oldest_event = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "oldest_event_timestamp_seconds"),
+		"Timestamp of the oldest event of an index, from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_newest_event = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "newest_event_timestamp_seconds"),
+		"Timestamp of the newest event of an index, from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_oldest_event_age = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "oldest_event_age_seconds"),
+		"Age of the oldest event of an index, to compare with its retention, from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_smartstore = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "smartstore_enabled"),
+		"Whether an index uses SmartStore remote storage, from data/indexes endpoint",
+		[]string{"index_name"}, nil,
+	)
+	index_buckets = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "index", "buckets"),
+		"Number of buckets of an index by state, from data/indexes-extended endpoint",
+		[]string{"index_name", "state"}, nil,
+	)
+
+	// indexerStatuses are the known statuses of an indexer
+	indexerStatuses = []string{"normal", "throttled", "stopped"}
 )
 
+// bytesPerMB converts sizes in MB returned by Splunk to bytes
+const bytesPerMB = 1024 * 1024
+
+// CollectorLogKey is the log key holding the name of the collector that logged
+const CollectorLogKey = "collector"
+
+// collectorLogger returns the logger of the named collector
+func collectorLogger(logger log.Logger, name string) log.Logger {
+	return log.With(logger, CollectorLogKey, name)
+}
+
+// collector gathers one family of measures from Splunk
+// it returns true if everything went well
+type collector interface {
+	CollectMeasures(ch chan<- prometheus.Metric) bool
+}
+
+// collectorFunc adapts a function to the collector interface
+type collectorFunc func(ch chan<- prometheus.Metric) bool
+
+func (f collectorFunc) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	return f(ch)
+}
+
 // Exporter collects Splunk stats from the given instance and exports them using the prometheus metrics package.
 type Exporter struct {
 	splunk         *splunklib.Splunk
@@ -41,11 +136,17 @@ type Exporter struct {
 	healthMetrics  *HealthManager
 	apiMetrics     map[string]*prometheus.Desc
 	apiMetricsMu   sync.Mutex // guards apiMetrics
+	indexesConf    config.Indexes
+	collectors     map[string]collector // every available collector, by name
+	enabled        []string             // names of collectors run on each scrape, sorted
 
 	// confMu serializes UpdateConf (triggered by SIGHUP, on its own goroutine)
 	// against Collect (triggered by an HTTP scrape): both read/write the same
 	// underlying splunk client fields (URL, Authenticator, TLSInsecureSkipVerify).
 	confMu sync.RWMutex
+
+	tokenWarnMu   sync.Mutex // guards tokenWarnedAt
+	tokenWarnedAt time.Time  // last warning about expiration of the token, zero when none since the token was configured
 }
 
 func (e *Exporter) UpdateConf(conf *config.Config) {
@@ -69,6 +170,11 @@ func (e *Exporter) UpdateConf(conf *config.Config) {
 	if err := applySplunkOpts(e.splunk.Client, opts, e.logger); err != nil {
 		level.Error(e.logger).Log("msg", "Could not update Splunk client", "err", err)
 	}
+
+	// a new token is warned about right away
+	e.tokenWarnMu.Lock()
+	e.tokenWarnedAt = time.Time{}
+	e.tokenWarnMu.Unlock()
 }
 
 type SplunkOpts struct {
@@ -126,33 +232,100 @@ func applySplunkOpts(client *splunkclient.Client, opts SplunkOpts, logger log.Lo
 	return nil
 }
 
-// New creates a new exporter for Splunk metrics
-func New(opts SplunkOpts, logger log.Logger, metricsConf []config.Metric) (*Exporter, error) {
-
+// NewSplunk creates a Splunk API wrapper from connection parameters
+func NewSplunk(opts SplunkOpts, logger log.Logger) (*splunklib.Splunk, error) {
 	client, err := getSplunkClient(opts, logger)
-
 	if err != nil {
-		level.Error(logger).Log("msg", "Could not get Splunk client", "err", err)
 		return nil, err
 	}
 
-	spk := splunklib.Splunk{
+	return &splunklib.Splunk{
 		Client: client,
 		Logger: logger,
+	}, nil
+}
+
+// New creates a new exporter for Splunk metrics
+func New(opts SplunkOpts, logger log.Logger, metricsConf []config.Metric, collectorsConf config.Collectors) (*Exporter, error) {
+
+	spk, err := NewSplunk(opts, logger)
+
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not get Splunk client", "err", err)
+		return nil, err
 	}
 
-	metricsManager := newMetricsManager(metricsConf, namespace, &spk, logger)
-	healthManager := newHealthManager(namespace, &spk, logger)
+	metricsManager := newMetricsManager(metricsConf, namespace, spk, collectorLogger(logger, "metrics"))
+	healthManager := newHealthManager(namespace, spk, collectorLogger(logger, "health"))
 
-	level.Info(logger).Log("msg", "Started Exporter", "instance", client.URL)
+	level.Info(logger).Log("msg", "Started Exporter", "instance", spk.Client.URL)
 
-	return &Exporter{
-		splunk:         &spk,
+	e := &Exporter{
+		splunk:         spk,
 		logger:         logger,
 		indexedMetrics: metricsManager,
 		healthMetrics:  healthManager,
 		apiMetrics:     make(map[string]*prometheus.Desc),
-	}, nil
+		indexesConf:    collectorsConf.Indexes,
+	}
+	e.collectors = map[string]collector{
+		"metrics":      collectorFunc(e.collectConfiguredMetrics),
+		"health":       collectorFunc(e.collectHealthMetrics),
+		"indexer":      collectorFunc(e.collectIndexerMetrics),
+		"license":      newLicenseManager(namespace, spk, collectorLogger(logger, "license")),
+		"cluster":      newClusterManager(namespace, spk, collectorLogger(logger, "cluster")),
+		"shc":          newSHClusterManager(namespace, spk, collectorLogger(logger, "shc")),
+		"queues":       newQueuesManager(namespace, spk, collectorLogger(logger, "queues")),
+		"forwarders":   newForwardersManager(namespace, spk, collectorLogger(logger, "forwarders"), collectorsConf.Forwarders),
+		"deployment":   newDeploymentManager(namespace, spk, collectorLogger(logger, "deployment"), collectorsConf.Deployment),
+		"scheduler":    newSchedulerManager(namespace, spk, collectorLogger(logger, "scheduler"), collectorsConf.Scheduler),
+		"kvstore":      newKVStoreManager(namespace, spk, collectorLogger(logger, "kvstore")),
+		"hec":          newHECManager(namespace, spk, collectorLogger(logger, "hec")),
+		"messages":     newMessagesManager(namespace, spk, collectorLogger(logger, "messages"), collectorsConf.Messages),
+		"server":       newServerManager(namespace, spk, collectorLogger(logger, "server")),
+		"disk":         newDiskManager(namespace, spk, collectorLogger(logger, "disk")),
+		"jobs":         newJobsManager(namespace, spk, collectorLogger(logger, "jobs"), collectorsConf.Jobs),
+		"alerts":       newAlertsManager(namespace, spk, collectorLogger(logger, "alerts")),
+		"acceleration": newAccelerationManager(namespace, spk, collectorLogger(logger, "acceleration")),
+		"smartstore":   newSmartStoreManager(namespace, spk, collectorLogger(logger, "smartstore")),
+		"workload":     newWorkloadManager(namespace, spk, collectorLogger(logger, "workload")),
+		"apps":         newAppsManager(namespace, spk, collectorLogger(logger, "apps")),
+		"users":        newUsersManager(namespace, spk, collectorLogger(logger, "users"), collectorsConf.Users),
+		"peers":        newPeersManager(namespace, spk, collectorLogger(logger, "peers")),
+	}
+	e.enabled = e.CollectorNames()
+
+	return e, nil
+}
+
+// CollectorNames returns the names of all collectors known by the exporter, sorted
+func (e *Exporter) CollectorNames() []string {
+	names := make([]string, 0, len(e.collectors))
+	for name := range e.collectors {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// EnableCollectors restricts collection to the given collectors
+// an error is returned if one of them does not exist, in which case enabled collectors are left untouched.
+func (e *Exporter) EnableCollectors(names ...string) error {
+	enabled := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := e.collectors[name]; !ok {
+			return fmt.Errorf("unknown collector %q, available collectors are: %s", name, strings.Join(e.CollectorNames(), ", "))
+		}
+		if !slices.Contains(enabled, name) {
+			enabled = append(enabled, name)
+		}
+	}
+	slices.Sort(enabled)
+
+	e.confMu.Lock()
+	e.enabled = enabled
+	e.confMu.Unlock()
+	return nil
 }
 
 // Describe describes all the metrics ever exported by the Splunk exporter. It
@@ -168,9 +341,24 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.confMu.RLock()
 	defer e.confMu.RUnlock()
 
-	ok := e.collectConfiguredMetrics(ch)
-	ok = e.collectHealthMetrics(ch) && ok
-	ok = e.collectIndexerMetrics(ch) && ok
+	ok := true
+	for _, name := range e.enabled {
+		begin := time.Now()
+		success := e.collectors[name].CollectMeasures(ch)
+		duration := time.Since(begin)
+
+		if !success {
+			level.Warn(e.logger).Log("msg", "collector failed", CollectorLogKey, name, "duration_seconds", duration.Seconds())
+		}
+		ch <- prometheus.MustNewConstMetric(
+			collectorDuration, prometheus.GaugeValue, duration.Seconds(), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			collectorSuccess, prometheus.GaugeValue, boolToFloat(success), name,
+		)
+		ok = success && ok
+	}
+	e.collectTokenExpiry(ch, time.Now())
 	if ok {
 		ch <- prometheus.MustNewConstMetric(
 			up, prometheus.GaugeValue, 1.0,
@@ -196,43 +384,104 @@ func (e *Exporter) collectHealthMetrics(ch chan<- prometheus.Metric) bool {
 
 func (e *Exporter) collectIndexerMetrics(ch chan<- prometheus.Metric) bool {
 	ret := true
-	level.Info(e.logger).Log("msg", "Collecting Indexer measures")
+	logger := collectorLogger(e.logger, "indexer")
+	level.Info(logger).Log("msg", "Collecting Indexer measures")
 	introspectionIndexer := splunklib.ServerIntrospectionIndexer{}
 	if err := e.splunk.Client.Read(&introspectionIndexer); err != nil {
-		level.Error(e.logger).Log("msg", "failed to read indexer data", "err", err)
+		level.Error(logger).Log("msg", "failed to read indexer data", "err", err)
 		ret = false
+	} else {
+		e.measureIndexer(ch, &introspectionIndexer)
 	}
 
-	throughput := introspectionIndexer.Content.AverageKBps / 1000
+	indexes := make([]splunklib.DataIndex, 0)
+	if err := e.splunk.ListAll(&indexes, nil); err != nil {
+		level.Error(logger).Log("msg", "failed to list indexes", "err", err)
+		ret = false
+	}
+	extended := make([]splunklib.DataIndexExtended, 0)
+	if err := e.splunk.ListAll(&extended, nil); err != nil {
+		level.Error(logger).Log("msg", "failed to list indexes details", "err", err)
+		ret = false
+	}
+	buckets := make(map[string]*splunklib.DataIndexExtended, len(extended))
+	for i := range extended {
+		buckets[extended[i].ID.Title] = &extended[i]
+	}
+
+	now := time.Now()
+	for _, i := range indexes {
+		level.Debug(logger).Log("msg", "processing index", "index", i.ID.Title)
+		e.measureIndex(ch, &i, buckets[i.ID.Title], now)
+		if e.indexesConf.Generic {
+			e.measureIndexFields(ch, &i)
+		}
+	}
+
+	level.Info(logger).Log("msg", "Done collecting Indexer measures")
+	return ret
+}
+
+// measureIndexer sends indexer throughput, and its status as a state set
+func (e *Exporter) measureIndexer(ch chan<- prometheus.Metric, indexer *splunklib.ServerIntrospectionIndexer) {
+	throughput := indexer.Content.AverageKBps / 1000
 
 	ch <- prometheus.MustNewConstMetric(
 		indexer_throughput, prometheus.GaugeValue, throughput,
 	)
 
-	indexes := make([]splunklib.DataIndex, 0)
-	if err := e.splunk.Client.List(&indexes); err != nil {
-		level.Error(e.logger).Log("msg", "failed to list indexes", "err", err)
-		ret = false
+	statuses := indexerStatuses
+	if !slices.Contains(statuses, indexer.Content.Status) {
+		statuses = append(slices.Clone(statuses), indexer.Content.Status)
 	}
-	for _, i := range indexes {
-		level.Debug(e.logger).Log("msg", "processing index", "index", i.ID.Title)
-		ret = ret && e.measureIndex(ch, &i)
+	for _, s := range statuses {
+		ch <- prometheus.MustNewConstMetric(
+			indexer_status, prometheus.GaugeValue, boolToFloat(s == indexer.Content.Status), s, indexer.Content.Reason,
+		)
 	}
+}
 
-	level.Info(e.logger).Log("msg", "Done collecting Indexer measures")
-	return ret
+// measureIndex sends size, retention and storage measures of one index
+// extended may be nil when bucket details are not available, and now is used to compute the age of the oldest event.
+func (e *Exporter) measureIndex(ch chan<- prometheus.Metric, index *splunklib.DataIndex, extended *splunklib.DataIndexExtended, now time.Time) {
+	name := index.ID.Title
+	c := index.Content
+
+	size := float64(c.CurrentDBSizeMB) * bytesPerMB
+	maxSize := float64(c.MaxTotalDataSizeMB) * bytesPerMB
+	ch <- prometheus.MustNewConstMetric(index_size, prometheus.GaugeValue, size, name)
+	ch <- prometheus.MustNewConstMetric(index_max_size, prometheus.GaugeValue, maxSize, name)
+	if maxSize > 0 {
+		ch <- prometheus.MustNewConstMetric(index_size_utilization, prometheus.GaugeValue, size/maxSize, name)
+	}
+	ch <- prometheus.MustNewConstMetric(index_events, prometheus.GaugeValue, float64(c.TotalEventCount), name)
+	ch <- prometheus.MustNewConstMetric(index_retention, prometheus.GaugeValue, float64(c.FrozenTimePeriodInSecs), name)
+	ch <- prometheus.MustNewConstMetric(index_smartstore, prometheus.GaugeValue, boolToFloat(c.RemotePath != ""), name)
+
+	// empty indexes have no event timestamps
+	if !c.MinTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(index_oldest_event, prometheus.GaugeValue, float64(c.MinTime.Unix()), name)
+		ch <- prometheus.MustNewConstMetric(index_oldest_event_age, prometheus.GaugeValue, now.Sub(c.MinTime.Time).Seconds(), name)
+	}
+	if !c.MaxTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(index_newest_event, prometheus.GaugeValue, float64(c.MaxTime.Unix()), name)
+	}
+
+	if extended != nil {
+		dirs := extended.Content.BucketDirs
+		ch <- prometheus.MustNewConstMetric(index_buckets, prometheus.GaugeValue, float64(dirs.Home.HotBucketCount), name, "hot")
+		ch <- prometheus.MustNewConstMetric(index_buckets, prometheus.GaugeValue, float64(dirs.Home.WarmBucketCount), name, "warm")
+		ch <- prometheus.MustNewConstMetric(index_buckets, prometheus.GaugeValue, float64(dirs.Cold.BucketCount), name, "cold")
+	}
 }
 
-// measureIndex returns measurements for one index, creating desc if they do not exist yet
-func (e *Exporter) measureIndex(ch chan<- prometheus.Metric, index *splunklib.DataIndex) bool {
-	ret := true
+// measureIndexFields sends every numeric field of one index, creating desc if they do not exist yet
+func (e *Exporter) measureIndexFields(ch chan<- prometheus.Metric, index *splunklib.DataIndex) {
 	indexName := index.ID.Title
-	for typ, ival := range index.Content {
+	for typ, ival := range index.Content.Fields {
 		var val float64
 		var err error
 
-		// FIXME add minDate and maxDate as titmestamps
-
 		switch v := ival.(type) {
 		case int:
 			val = float64(v)
@@ -251,7 +500,14 @@ func (e *Exporter) measureIndex(ch chan<- prometheus.Metric, index *splunklib.Da
 		help := fmt.Sprintf("Index %s from Splunk data/indexes API", typ)
 		e.CreateIfNeededThenMeasure(ch, "index", name, help, val, []string{"index_name"}, []string{indexName})
 	}
-	return ret
+}
+
+// boolToFloat converts a boolean to a prometheus value, 1 for true and 0 for false
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1.0
+	}
+	return 0.0
 }
 
 // normalizeName will format a string so it can be accepted by prometheus as a metric name or label
@@ -1,13 +1,22 @@
 package exporter
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/splunk/go-splunk-client/pkg/authenticators"
+	splunkclient "github.com/splunk/go-splunk-client/pkg/client"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,7 +30,7 @@ func TestNew_InvalidSplunkURL(t *testing.T) {
 	defer w.Close()
 	logger := log.NewJSONLogger(w)
 
-	exp, err := New(SplunkOpts{URI: ""}, logger, nil)
+	exp, err := New(SplunkOpts{URI: ""}, logger, nil, config.Collectors{})
 
 	assert.Error(t, err)
 	assert.Nil(t, exp)
@@ -32,12 +41,11 @@ func TestNew_InvalidSplunkURL(t *testing.T) {
 // while Collect (triggered from an HTTP scrape goroutine) reads those same
 // fields. Run with `go test -race` to observe the data race.
 func TestExporter_ConcurrentUpdateConfAndCollect(t *testing.T) {
-	_, w, _ := os.Pipe()
-	defer w.Close()
-	logger := log.NewJSONLogger(w)
+	// a no-op logger: concurrent writes to an unread pipe block once its buffer is full
+	logger := log.NewNopLogger()
 
 	// unroutable-but-immediately-refused address: fails fast, no real network needed.
-	exp, err := New(SplunkOpts{URI: "http://127.0.0.1:1"}, logger, nil)
+	exp, err := New(SplunkOpts{URI: "http://127.0.0.1:1"}, logger, nil, config.Collectors{})
 	if err != nil {
 		t.Fatalf("failed to build exporter: %v", err)
 	}
@@ -111,3 +119,223 @@ func TestExporter_ConcurrentCreateIfNeededThenMeasure(t *testing.T) {
 	}
 	close(done)
 }
+
+func TestEnableCollectors(t *testing.T) {
+	logger := log.NewNopLogger()
+
+	exp, err := New(SplunkOpts{URI: "http://127.0.0.1:1"}, logger, nil, config.Collectors{})
+	assert.NoError(t, err)
+	assert.Equal(t, exp.CollectorNames(), exp.enabled, "all collectors are enabled by default")
+
+	assert.NoError(t, exp.EnableCollectors("health", "health"))
+	assert.Equal(t, []string{"health"}, exp.enabled)
+
+	assert.Error(t, exp.EnableCollectors("health", "unknown"))
+	assert.Equal(t, []string{"health"}, exp.enabled, "a bad selection must leave enabled collectors untouched")
+}
+
+// Collect reports the success of every enabled collector, and only of those
+func TestCollect_CollectorSuccess(t *testing.T) {
+	logger := log.NewNopLogger()
+
+	exp, err := New(SplunkOpts{URI: "http://127.0.0.1:1"}, logger, nil, config.Collectors{})
+	assert.NoError(t, err)
+	exp.collectors = map[string]collector{
+		"good": collectorFunc(func(ch chan<- prometheus.Metric) bool { return true }),
+		"bad":  collectorFunc(func(ch chan<- prometheus.Metric) bool { return false }),
+		"off":  collectorFunc(func(ch chan<- prometheus.Metric) bool { return false }),
+	}
+	assert.NoError(t, exp.EnableCollectors("good", "bad"))
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(exp)
+
+	expected := `
+# HELP splunk_exporter_collector_success Whether a collector succeeded during the last scrape.
+# TYPE splunk_exporter_collector_success gauge
+splunk_exporter_collector_success{collector="bad"} 0
+splunk_exporter_collector_success{collector="good"} 1
+# HELP splunk_exporter_up Was the last query of Splunk successful.
+# TYPE splunk_exporter_up gauge
+splunk_exporter_up 0
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(expected), "splunk_exporter_collector_success", "splunk_exporter_up")
+	assert.NoError(t, err)
+}
+
+// readTestEntries loads entries recorded from a Splunk REST API list response
+func readTestEntries[T any](t *testing.T, file string) []T {
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+
+	var response struct {
+		Entry []T `json:"entry"`
+	}
+	assert.NoError(t, json.Unmarshal(content, &response))
+	return response.Entry
+}
+
+// testCollector turns a collect function into an unchecked prometheus.Collector,
+// so that what it measures can be compared using testutil.
+type testCollector func(ch chan<- prometheus.Metric)
+
+func (c testCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c testCollector) Collect(ch chan<- prometheus.Metric) { c(ch) }
+
+// newTestdataSplunk returns a Splunk client to a fake server answering each REST API path with a recorded testdata file
+// a path can be followed by a query parameter, like "/services/cluster/manager/fixup?level=streaming", to answer only requests with that value.
+// paths not in responses get a 404.
+func newTestdataSplunk(t *testing.T, responses map[string]string) *splunklib.Splunk {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := "", false
+		for key, value := range r.URL.Query() {
+			if file, ok = responses[r.URL.Path+"?"+key+"="+value[0]]; ok {
+				break
+			}
+		}
+		if !ok {
+			file, ok = responses[r.URL.Path]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"messages": [{"type": "ERROR", "text": "Not Found"}]}`))
+			return
+		}
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+
+	return &splunklib.Splunk{
+		Client: &splunkclient.Client{
+			URL:           server.URL,
+			Authenticator: authenticators.Token{Token: "test"},
+		},
+		Logger: log.NewNopLogger(),
+	}
+}
+
+func TestMeasureIndexer(t *testing.T) {
+	exp := &Exporter{logger: log.NewNopLogger()}
+	indexer := readTestEntries[splunklib.ServerIntrospectionIndexer](t, "testdata/serverintrospectionindexer.json")
+	assert.Len(t, indexer, 1)
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		exp.measureIndexer(ch, &indexer[0])
+	})
+
+	expected := `
+# HELP splunk_exporter_indexer_status Status of instance indexer, 1 for the current one, from server/introspection/indexer endpoint
+# TYPE splunk_exporter_indexer_status gauge
+splunk_exporter_indexer_status{reason="Indexer is throttled because the index queue is blocked.",status="normal"} 0
+splunk_exporter_indexer_status{reason="Indexer is throttled because the index queue is blocked.",status="stopped"} 0
+splunk_exporter_indexer_status{reason="Indexer is throttled because the index queue is blocked.",status="throttled"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "splunk_exporter_indexer_status"))
+}
+
+func TestMeasureIndex(t *testing.T) {
+	exp := &Exporter{logger: log.NewNopLogger()}
+	indexes := readTestEntries[splunklib.DataIndex](t, "testdata/dataindexes.json")
+	extended := readTestEntries[splunklib.DataIndexExtended](t, "testdata/dataindexesextended.json")
+	assert.Len(t, indexes, 4)
+	assert.Len(t, extended, 4)
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		for i := range indexes {
+			// no bucket details for the empty index
+			if indexes[i].ID.Title == "metrics_empty" {
+				exp.measureIndex(ch, &indexes[i], nil, now)
+			} else {
+				exp.measureIndex(ch, &indexes[i], &extended[i], now)
+			}
+		}
+	})
+
+	expected := `
+# HELP splunk_exporter_index_buckets Number of buckets of an index by state, from data/indexes-extended endpoint
+# TYPE splunk_exporter_index_buckets gauge
+splunk_exporter_index_buckets{index_name="_internal",state="cold"} 0
+splunk_exporter_index_buckets{index_name="_internal",state="hot"} 1
+splunk_exporter_index_buckets{index_name="_internal",state="warm"} 12
+splunk_exporter_index_buckets{index_name="main",state="cold"} 7
+splunk_exporter_index_buckets{index_name="main",state="hot"} 3
+splunk_exporter_index_buckets{index_name="main",state="warm"} 40
+splunk_exporter_index_buckets{index_name="smartstore",state="cold"} 0
+splunk_exporter_index_buckets{index_name="smartstore",state="hot"} 2
+splunk_exporter_index_buckets{index_name="smartstore",state="warm"} 150
+# HELP splunk_exporter_index_oldest_event_age_seconds Age of the oldest event of an index, to compare with its retention, from data/indexes endpoint
+# TYPE splunk_exporter_index_oldest_event_age_seconds gauge
+splunk_exporter_index_oldest_event_age_seconds{index_name="_internal"} 2592000
+splunk_exporter_index_oldest_event_age_seconds{index_name="main"} 10475755
+splunk_exporter_index_oldest_event_age_seconds{index_name="smartstore"} 31654800
+# HELP splunk_exporter_index_retention_seconds Age after which events of an index are frozen (frozenTimePeriodInSecs), from data/indexes endpoint
+# TYPE splunk_exporter_index_retention_seconds gauge
+splunk_exporter_index_retention_seconds{index_name="_internal"} 2592000
+splunk_exporter_index_retention_seconds{index_name="main"} 188697600
+splunk_exporter_index_retention_seconds{index_name="metrics_empty"} 188697600
+splunk_exporter_index_retention_seconds{index_name="smartstore"} 31536000
+# HELP splunk_exporter_index_size_bytes Size of an index on disk, from data/indexes endpoint
+# TYPE splunk_exporter_index_size_bytes gauge
+splunk_exporter_index_size_bytes{index_name="_internal"} 419430400
+splunk_exporter_index_size_bytes{index_name="main"} 2147483648
+splunk_exporter_index_size_bytes{index_name="metrics_empty"} 1048576
+splunk_exporter_index_size_bytes{index_name="smartstore"} 10737418240
+# HELP splunk_exporter_index_size_utilization_ratio Size of an index divided by its maximum size, from data/indexes endpoint
+# TYPE splunk_exporter_index_size_utilization_ratio gauge
+splunk_exporter_index_size_utilization_ratio{index_name="_internal"} 0.0008
+splunk_exporter_index_size_utilization_ratio{index_name="main"} 0.004096
+splunk_exporter_index_size_utilization_ratio{index_name="metrics_empty"} 2e-06
+splunk_exporter_index_size_utilization_ratio{index_name="smartstore"} 0.25
+# HELP splunk_exporter_index_smartstore_enabled Whether an index uses SmartStore remote storage, from data/indexes endpoint
+# TYPE splunk_exporter_index_smartstore_enabled gauge
+splunk_exporter_index_smartstore_enabled{index_name="_internal"} 0
+splunk_exporter_index_smartstore_enabled{index_name="main"} 0
+splunk_exporter_index_smartstore_enabled{index_name="metrics_empty"} 0
+splunk_exporter_index_smartstore_enabled{index_name="smartstore"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_index_buckets",
+		"splunk_exporter_index_oldest_event_age_seconds",
+		"splunk_exporter_index_retention_seconds",
+		"splunk_exporter_index_size_bytes",
+		"splunk_exporter_index_size_utilization_ratio",
+		"splunk_exporter_index_smartstore_enabled",
+	))
+	// empty index has no event timestamps
+	assert.Equal(t, 3, testutil.CollectAndCount(c, "splunk_exporter_index_oldest_event_timestamp_seconds"))
+	assert.Equal(t, 3, testutil.CollectAndCount(c, "splunk_exporter_index_newest_event_timestamp_seconds"))
+}
+
+func TestCollectIndexerMetrics_Generic(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/introspection/indexer": "testdata/serverintrospectionindexer.json",
+		"/services/data/indexes":                 "testdata/dataindexes.json",
+		"/services/data/indexes-extended":        "testdata/dataindexesextended.json",
+	})
+
+	for _, generic := range []bool{false, true} {
+		exp := &Exporter{
+			splunk:      spk,
+			logger:      log.NewNopLogger(),
+			apiMetrics:  make(map[string]*prometheus.Desc),
+			indexesConf: config.Indexes{Generic: generic},
+		}
+		var ok bool
+		c := testCollector(func(ch chan<- prometheus.Metric) {
+			ok = exp.collectIndexerMetrics(ch)
+		})
+
+		assert.Equal(t, 4, testutil.CollectAndCount(c, "splunk_exporter_index_size_bytes"))
+		assert.True(t, ok)
+		if generic {
+			assert.Equal(t, 4, testutil.CollectAndCount(c, "splunk_exporter_index_currentDBSizeMB"))
+		} else {
+			assert.Equal(t, 0, testutil.CollectAndCount(c, "splunk_exporter_index_currentDBSizeMB"))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package exporter
+
+import (
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultMaxForwarders is the number of forwarders exported when not configured
+const defaultMaxForwarders = 1000
+
+// ForwardersManager collects connections of forwarders, as seen by receiving indexers
+type ForwardersManager struct {
+	splunk               *splunklib.Splunk // Splunk client
+	logger               log.Logger
+	max                  int // maximum number of forwarders exported
+	receivingDescriptor  *prometheus.Desc
+	forwardersDescriptor *prometheus.Desc
+	lastSeenDescriptor   *prometheus.Desc
+	throughputDescriptor *prometheus.Desc
+	infoDescriptor       *prometheus.Desc
+}
+
+func newForwardersManager(namespace string, spk *splunklib.Splunk, logger log.Logger, conf config.Forwarders) *ForwardersManager {
+
+	level.Debug(logger).Log("msg", "Initiating forwarders manager")
+
+	max := conf.Max
+	if max <= 0 {
+		max = defaultMaxForwarders
+	}
+
+	fm := ForwardersManager{
+		splunk: spk,
+		logger: logger,
+		max:    max,
+		receivingDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "forwarder", "receiving_port_enabled"),
+			"Whether a port receiving data from forwarders is enabled, from data/inputs/tcp/cooked API",
+			[]string{"port"}, nil,
+		),
+		forwardersDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "forwarder", "connected"),
+			"Number of forwarders that sent data in the last 24 hours, including those over the exported limit, from tcpin_connections metrics",
+			nil, nil,
+		),
+		lastSeenDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "forwarder", "last_seen_timestamp_seconds"),
+			"Last time an indexer reported a connection from a forwarder, from tcpin_connections metrics",
+			[]string{"forwarder"}, nil,
+		),
+		throughputDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "forwarder", "throughput_bytes_per_second"),
+			"Latest throughput received from a forwarder, from tcpin_connections metrics",
+			[]string{"forwarder"}, nil,
+		),
+		infoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "forwarder", "info"),
+			"Information about a forwarder, from tcpin_connections metrics",
+			[]string{"forwarder", "version", "type", "source_ip"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating forwarders manager")
+	return &fm
+}
+
+func (fm *ForwardersManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(fm.logger).Log("msg", "Collecting Forwarders measures")
+	ret := true
+
+	inputs := make([]splunklib.DataInputTCPCooked, 0)
+	if err := fm.splunk.ListAll(&inputs, nil); err != nil {
+		level.Error(fm.logger).Log("msg", "failed to list receiving ports", "err", err)
+		ret = false
+	} else {
+		fm.collectReceivingPorts(ch, inputs)
+	}
+
+	// indexers forward their _internal logs in most deployments, so the scraped instance does not need to be a receiver
+	forwarders, total, err := fm.splunk.GetForwarderConnections(fm.max)
+	if err != nil {
+		level.Error(fm.logger).Log("msg", "failed to get forwarder connections", "err", err)
+		ret = false
+	} else {
+		fm.collectForwarders(ch, forwarders, total)
+	}
+
+	level.Info(fm.logger).Log("msg", "Done collecting Forwarders measures", "success", ret)
+	return ret
+}
+
+// collectReceivingPorts sends whether each receiving port is enabled
+func (fm *ForwardersManager) collectReceivingPorts(ch chan<- prometheus.Metric, inputs []splunklib.DataInputTCPCooked) {
+	for _, i := range inputs {
+		ch <- prometheus.MustNewConstMetric(
+			fm.receivingDescriptor, prometheus.GaugeValue, boolToFloat(!bool(i.Content.Disabled)), i.ID.Title,
+		)
+	}
+}
+
+// collectForwarders sends last seen time, throughput and version of each forwarder
+// forwarders over the configured limit have already been dropped by the search, only their total count is known.
+func (fm *ForwardersManager) collectForwarders(ch chan<- prometheus.Metric, forwarders []splunklib.ForwarderConnection, total int) {
+	if total > fm.max {
+		level.Warn(fm.logger).Log("msg", "too many forwarders, only the most recently seen are exported", "forwarders", total, "max", fm.max)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		fm.forwardersDescriptor, prometheus.GaugeValue, float64(total),
+	)
+
+	for _, f := range forwarders {
+		ch <- prometheus.MustNewConstMetric(
+			fm.lastSeenDescriptor, prometheus.GaugeValue, float64(f.LastSeen.Unix()), f.Hostname,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			fm.throughputDescriptor, prometheus.GaugeValue, f.Throughput, f.Hostname,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			fm.infoDescriptor, prometheus.GaugeValue, 1, f.Hostname, f.Version, f.Type, f.SourceIP,
+		)
+	}
+}
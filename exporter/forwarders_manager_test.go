@@ -0,0 +1,68 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardersReceivingPorts(t *testing.T) {
+	fm := newForwardersManager(namespace, nil, log.NewNopLogger(), config.Forwarders{})
+	inputs := readTestEntries[splunklib.DataInputTCPCooked](t, "testdata/datainputstcpcooked.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		fm.collectReceivingPorts(ch, inputs)
+	})
+
+	expected := `
+# HELP splunk_exporter_forwarder_receiving_port_enabled Whether a port receiving data from forwarders is enabled, from data/inputs/tcp/cooked API
+# TYPE splunk_exporter_forwarder_receiving_port_enabled gauge
+splunk_exporter_forwarder_receiving_port_enabled{port="9997"} 1
+splunk_exporter_forwarder_receiving_port_enabled{port="9998"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestForwarders(t *testing.T) {
+	fm := newForwardersManager(namespace, nil, log.NewNopLogger(), config.Forwarders{Max: 2})
+	assert.Equal(t, 2, fm.max)
+	forwarders := []splunklib.ForwarderConnection{
+		{Hostname: "uf1", SourceIP: "10.0.0.1", Version: "9.2.1", Type: "uf", LastSeen: time.Unix(1714640400, 0), Throughput: 2048},
+		{Hostname: "hf1", SourceIP: "10.0.0.2", Version: "9.1.0", Type: "full", LastSeen: time.Unix(1714640000, 0), Throughput: 0},
+	}
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		fm.collectForwarders(ch, forwarders, 3)
+	})
+
+	expected := `
+# HELP splunk_exporter_forwarder_connected Number of forwarders that sent data in the last 24 hours, including those over the exported limit, from tcpin_connections metrics
+# TYPE splunk_exporter_forwarder_connected gauge
+splunk_exporter_forwarder_connected 3
+# HELP splunk_exporter_forwarder_info Information about a forwarder, from tcpin_connections metrics
+# TYPE splunk_exporter_forwarder_info gauge
+splunk_exporter_forwarder_info{forwarder="hf1",source_ip="10.0.0.2",type="full",version="9.1.0"} 1
+splunk_exporter_forwarder_info{forwarder="uf1",source_ip="10.0.0.1",type="uf",version="9.2.1"} 1
+# HELP splunk_exporter_forwarder_last_seen_timestamp_seconds Last time an indexer reported a connection from a forwarder, from tcpin_connections metrics
+# TYPE splunk_exporter_forwarder_last_seen_timestamp_seconds gauge
+splunk_exporter_forwarder_last_seen_timestamp_seconds{forwarder="hf1"} 1.714640000e+09
+splunk_exporter_forwarder_last_seen_timestamp_seconds{forwarder="uf1"} 1.7146404e+09
+# HELP splunk_exporter_forwarder_throughput_bytes_per_second Latest throughput received from a forwarder, from tcpin_connections metrics
+# TYPE splunk_exporter_forwarder_throughput_bytes_per_second gauge
+splunk_exporter_forwarder_throughput_bytes_per_second{forwarder="hf1"} 0
+splunk_exporter_forwarder_throughput_bytes_per_second{forwarder="uf1"} 2048
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestForwardersDefaultMax(t *testing.T) {
+	fm := newForwardersManager(namespace, nil, log.NewNopLogger(), config.Forwarders{})
+	assert.Equal(t, defaultMaxForwarders, fm.max)
+}
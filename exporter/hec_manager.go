@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"strings"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// hecTokenPrefix prefixes the title of HEC token inputs
+const hecTokenPrefix = "http://"
+
+// HECManager collects HTTP Event Collector health and token statistics
+// token values are secrets, they are never read nor exported, tokens are identified by their name.
+type HECManager struct {
+	splunk                  *splunklib.Splunk // Splunk client
+	logger                  log.Logger
+	healthyDescriptor       *prometheus.Desc
+	tokenEnabledDescriptor  *prometheus.Desc
+	tokenIndexDescriptor    *prometheus.Desc
+	tokenBytesDescriptor    *prometheus.Desc
+	tokenEventsDescriptor   *prometheus.Desc
+	tokenRequestsDescriptor *prometheus.Desc
+	tokenErrorsDescriptor   *prometheus.Desc
+}
+
+func newHECManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *HECManager {
+
+	level.Debug(logger).Log("msg", "Initiating HEC manager")
+
+	hm := HECManager{
+		splunk: spk,
+		logger: logger,
+		healthyDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "hec", "healthy"),
+			"Whether HTTP Event Collector accepts events, from services/collector/health API",
+			nil, nil,
+		),
+		tokenEnabledDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "hec", "token_enabled"),
+			"Whether a HEC token is enabled, from data/inputs/http API",
+			[]string{"token"}, nil,
+		),
+		tokenIndexDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "hec", "token_allowed_index"),
+			"Index a HEC token is allowed to send to, \"*\" when any index is allowed, from data/inputs/http API",
+			[]string{"token", "index"}, nil,
+		),
+		tokenBytesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "hec", "token_received_bytes"),
+			"Bytes received with a HEC token in the last 5 minutes, from introspection data",
+			[]string{"token"}, nil,
+		),
+		tokenEventsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "hec", "token_events"),
+			"Events received with a HEC token in the last 5 minutes, from introspection data",
+			[]string{"token"}, nil,
+		),
+		tokenRequestsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "hec", "token_requests"),
+			"Requests received with a HEC token in the last 5 minutes, from introspection data",
+			[]string{"token"}, nil,
+		),
+		tokenErrorsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "hec", "token_errors"),
+			"Requests in error with a HEC token in the last 5 minutes, from introspection data",
+			[]string{"token"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating HEC manager")
+	return &hm
+}
+
+func (hm *HECManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(hm.logger).Log("msg", "Collecting HEC measures")
+
+	tokens := make([]splunklib.DataInputHTTP, 0)
+	if err := hm.splunk.ListAll(&tokens, nil); err != nil {
+		level.Error(hm.logger).Log("msg", "failed to list HEC tokens", "err", err)
+		return false
+	}
+	if len(tokens) == 0 {
+		level.Debug(hm.logger).Log("msg", "No HEC token, skipping HEC health and activity")
+		return true
+	}
+	hm.collectTokens(ch, tokens)
+
+	ret := true
+	healthy, text, err := hm.splunk.GetHECHealth()
+	if err != nil {
+		level.Error(hm.logger).Log("msg", "failed to read HEC health", "err", err)
+		ret = false
+	} else {
+		if !healthy {
+			level.Warn(hm.logger).Log("msg", "HEC is unhealthy", "reason", text)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			hm.healthyDescriptor, prometheus.GaugeValue, boolToFloat(healthy),
+		)
+	}
+
+	activities, err := hm.splunk.GetHECTokenActivity()
+	if err != nil {
+		level.Error(hm.logger).Log("msg", "failed to get HEC token activity", "err", err)
+		ret = false
+	} else {
+		hm.collectActivity(ch, activities)
+	}
+
+	level.Info(hm.logger).Log("msg", "Done collecting HEC measures", "success", ret)
+	return ret
+}
+
+// collectTokens sends enabled state and allowed indexes of each token
+func (hm *HECManager) collectTokens(ch chan<- prometheus.Metric, tokens []splunklib.DataInputHTTP) {
+	for _, t := range tokens {
+		name := strings.TrimPrefix(splunklib.EntryName(t.ID), hecTokenPrefix)
+		ch <- prometheus.MustNewConstMetric(
+			hm.tokenEnabledDescriptor, prometheus.GaugeValue, boolToFloat(!bool(t.Content.Disabled)), name,
+		)
+
+		indexes := t.Content.Indexes
+		if len(indexes) == 0 {
+			indexes = []string{"*"}
+		}
+		for _, i := range indexes {
+			ch <- prometheus.MustNewConstMetric(
+				hm.tokenIndexDescriptor, prometheus.GaugeValue, 1, name, i,
+			)
+		}
+	}
+}
+
+// collectActivity sends what each token received recently
+func (hm *HECManager) collectActivity(ch chan<- prometheus.Metric, activities []splunklib.HECTokenActivity) {
+	for _, a := range activities {
+		ch <- prometheus.MustNewConstMetric(
+			hm.tokenBytesDescriptor, prometheus.GaugeValue, a.Bytes, a.Token,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			hm.tokenEventsDescriptor, prometheus.GaugeValue, a.Events, a.Token,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			hm.tokenRequestsDescriptor, prometheus.GaugeValue, a.Requests, a.Token,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			hm.tokenErrorsDescriptor, prometheus.GaugeValue, a.Errors, a.Token,
+		)
+	}
+}
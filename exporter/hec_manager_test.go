@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHECManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/data/inputs/http": "testdata/datainputshttp.json",
+		"/services/collector/health": "testdata/collectorhealth.json",
+		"/services/search/v2/jobs":   "testdata/searchhecactivity.json",
+	})
+	hm := newHECManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = hm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_hec_healthy Whether HTTP Event Collector accepts events, from services/collector/health API
+# TYPE splunk_exporter_hec_healthy gauge
+splunk_exporter_hec_healthy 1
+# HELP splunk_exporter_hec_token_allowed_index Index a HEC token is allowed to send to, "*" when any index is allowed, from data/inputs/http API
+# TYPE splunk_exporter_hec_token_allowed_index gauge
+splunk_exporter_hec_token_allowed_index{index="*",token="legacy_app"} 1
+splunk_exporter_hec_token_allowed_index{index="k8s",token="k8s_logs"} 1
+splunk_exporter_hec_token_allowed_index{index="k8s_audit",token="k8s_logs"} 1
+# HELP splunk_exporter_hec_token_enabled Whether a HEC token is enabled, from data/inputs/http API
+# TYPE splunk_exporter_hec_token_enabled gauge
+splunk_exporter_hec_token_enabled{token="k8s_logs"} 1
+splunk_exporter_hec_token_enabled{token="legacy_app"} 0
+# HELP splunk_exporter_hec_token_errors Requests in error with a HEC token in the last 5 minutes, from introspection data
+# TYPE splunk_exporter_hec_token_errors gauge
+splunk_exporter_hec_token_errors{token="k8s_logs"} 4
+# HELP splunk_exporter_hec_token_received_bytes Bytes received with a HEC token in the last 5 minutes, from introspection data
+# TYPE splunk_exporter_hec_token_received_bytes gauge
+splunk_exporter_hec_token_received_bytes{token="k8s_logs"} 5.24288e+07
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_hec_healthy",
+		"splunk_exporter_hec_token_allowed_index",
+		"splunk_exporter_hec_token_enabled",
+		"splunk_exporter_hec_token_errors",
+		"splunk_exporter_hec_token_received_bytes",
+	))
+	assert.True(t, ok)
+
+	// token values are secrets
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	for _, f := range families {
+		_, err := expfmt.MetricFamilyToText(&out, f)
+		assert.NoError(t, err)
+	}
+	assert.NotContains(t, out.String(), "11111111-2222-3333-4444-555555555555")
+	assert.NotContains(t, out.String(), "66666666-7777-8888-9999-000000000000")
+}
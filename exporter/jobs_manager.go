@@ -0,0 +1,234 @@
+package exporter
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultMaxUsers is the number of users exported when not configured
+const defaultMaxUsers = 50
+
+// otherUser sums the jobs of users over the exported limit
+const otherUser = "_other"
+
+var (
+	// jobStates are the states search jobs are counted in
+	jobStates = []string{"queued", "running", "finished"}
+	// jobTypes are the types search jobs are counted in
+	jobTypes = []string{"adhoc", "scheduled", "summary"}
+)
+
+// jobState maps the dispatch state of a search job to the state it is counted in
+func jobState(dispatchState string) string {
+	switch dispatchState {
+	case "QUEUED":
+		return "queued"
+	case "DONE", "FAILED":
+		return "finished"
+	default:
+		// PARSING, RUNNING, FINALIZING and PAUSED jobs hold a search slot
+		return "running"
+	}
+}
+
+// jobType tells the type of a search job from its search ID
+func jobType(sid string) string {
+	switch {
+	case strings.HasPrefix(sid, "_ACCELERATE_") || strings.Contains(sid, "_summarize_"):
+		// data model and report acceleration
+		return "summary"
+	case strings.HasPrefix(sid, "scheduler_") || strings.HasPrefix(sid, "rt_scheduler_"):
+		return "scheduled"
+	default:
+		return "adhoc"
+	}
+}
+
+// JobsManager collects a summary of search jobs, and search concurrency limits
+type JobsManager struct {
+	splunk           *splunklib.Splunk // Splunk client
+	logger           log.Logger
+	maxUsers         int // maximum number of users exported
+	byTypeDescriptor *prometheus.Desc
+	byAppDescriptor  *prometheus.Desc
+	byUserDescriptor *prometheus.Desc
+	limitDescriptor  *prometheus.Desc
+}
+
+func newJobsManager(namespace string, spk *splunklib.Splunk, logger log.Logger, conf config.Jobs) *JobsManager {
+
+	level.Debug(logger).Log("msg", "Initiating jobs manager")
+
+	maxUsers := conf.MaxUsers
+	if maxUsers <= 0 {
+		maxUsers = defaultMaxUsers
+	}
+
+	jm := JobsManager{
+		splunk:   spk,
+		logger:   logger,
+		maxUsers: maxUsers,
+		byTypeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "jobs"),
+			"Number of search jobs by type and state, from search/v2/jobs API",
+			[]string{"type", "state"}, nil,
+		),
+		byAppDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "app_jobs"),
+			"Number of search jobs of an app by state, from search/v2/jobs API",
+			[]string{"app", "state"}, nil,
+		),
+		byUserDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "user_jobs"),
+			"Number of search jobs of a user by state, users over the configured limit are summed as \"_other\", from search/v2/jobs API",
+			[]string{"user", "state"}, nil,
+		),
+		limitDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "concurrency_limit"),
+			"Maximum number of concurrent searches by type, from server/status/limits/search-concurrency API",
+			[]string{"type"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating jobs manager")
+	return &jm
+}
+
+func (jm *JobsManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(jm.logger).Log("msg", "Collecting Jobs measures")
+	ret := true
+
+	// only the state is needed, jobs have dozens of fields
+	params := url.Values{"f": []string{"dispatchState"}}
+	jobs := make([]splunklib.SearchJob, 0)
+	if err := jm.splunk.ListAll(&jobs, params); err != nil {
+		level.Error(jm.logger).Log("msg", "failed to list search jobs", "err", err)
+		ret = false
+	} else {
+		jm.collectJobs(ch, jobs)
+	}
+
+	limits := splunklib.ServerSearchConcurrency{}
+	if err := jm.splunk.Client.Read(&limits); err != nil {
+		level.Error(jm.logger).Log("msg", "failed to read search concurrency limits", "err", err)
+		ret = false
+	} else {
+		jm.collectLimits(ch, &limits)
+	}
+
+	level.Info(jm.logger).Log("msg", "Done collecting Jobs measures", "success", ret)
+	return ret
+}
+
+// collectJobs counts search jobs by type, app and user, in each state
+func (jm *JobsManager) collectJobs(ch chan<- prometheus.Metric, jobs []splunklib.SearchJob) {
+	byType := make(map[string]map[string]float64, len(jobTypes))
+	for _, t := range jobTypes {
+		byType[t] = newStateCounts()
+	}
+	byApp := make(map[string]map[string]float64)
+	byUser := make(map[string]map[string]float64)
+
+	for _, j := range jobs {
+		state := jobState(j.Content.DispatchState)
+		byType[jobType(j.ID.Title)][state]++
+
+		if _, ok := byApp[j.ACL.App]; !ok {
+			byApp[j.ACL.App] = newStateCounts()
+		}
+		byApp[j.ACL.App][state]++
+
+		if _, ok := byUser[j.ACL.Owner]; !ok {
+			byUser[j.ACL.Owner] = newStateCounts()
+		}
+		byUser[j.ACL.Owner][state]++
+	}
+
+	if len(byUser) > jm.maxUsers {
+		level.Debug(jm.logger).Log("msg", "too many users running searches, summing the least active ones", "users", len(byUser), "max", jm.maxUsers)
+		byUser = capStateCounts(byUser, jm.maxUsers, otherUser)
+	}
+
+	for typ, counts := range byType {
+		for state, count := range counts {
+			ch <- prometheus.MustNewConstMetric(jm.byTypeDescriptor, prometheus.GaugeValue, count, typ, state)
+		}
+	}
+	for app, counts := range byApp {
+		for state, count := range counts {
+			ch <- prometheus.MustNewConstMetric(jm.byAppDescriptor, prometheus.GaugeValue, count, app, state)
+		}
+	}
+	for user, counts := range byUser {
+		for state, count := range counts {
+			ch <- prometheus.MustNewConstMetric(jm.byUserDescriptor, prometheus.GaugeValue, count, user, state)
+		}
+	}
+}
+
+// collectLimits sends the maximum number of concurrent searches of each type
+func (jm *JobsManager) collectLimits(ch chan<- prometheus.Metric, limits *splunklib.ServerSearchConcurrency) {
+	c := limits.Content
+	for typ, limit := range map[string]splunklib.Number{
+		"historical":           c.MaxHistSearches,
+		"historical_scheduled": c.MaxHistScheduledSearches,
+		"realtime":             c.MaxRTSearches,
+		"realtime_scheduled":   c.MaxRTScheduledSearches,
+		"auto_summary":         c.MaxAutoSummarySearches,
+	} {
+		ch <- prometheus.MustNewConstMetric(jm.limitDescriptor, prometheus.GaugeValue, float64(limit), typ)
+	}
+}
+
+// newStateCounts returns a zero count for each job state
+func newStateCounts() map[string]float64 {
+	counts := make(map[string]float64, len(jobStates))
+	for _, s := range jobStates {
+		counts[s] = 0
+	}
+	return counts
+}
+
+// capStateCounts keeps the max keys with the most jobs, and sums the others under the other key
+func capStateCounts(counts map[string]map[string]float64, max int, other string) map[string]map[string]float64 {
+	total := func(c map[string]float64) float64 {
+		t := 0.0
+		for _, v := range c {
+			t += v
+		}
+		return t
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	// most jobs first, by name for a stable result
+	slices.SortFunc(keys, func(a, b string) int {
+		if ta, tb := total(counts[a]), total(counts[b]); ta != tb {
+			if ta > tb {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	capped := make(map[string]map[string]float64, max+1)
+	for _, k := range keys[:max] {
+		capped[k] = counts[k]
+	}
+	capped[other] = newStateCounts()
+	for _, k := range keys[max:] {
+		for state, v := range counts[k] {
+			capped[other][state] += v
+		}
+	}
+	return capped
+}
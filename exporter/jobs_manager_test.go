@@ -0,0 +1,73 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobsManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/search/v2/jobs":                          "testdata/searchjobs.json",
+		"/services/server/status/limits/search-concurrency": "testdata/serversearchconcurrency.json",
+	})
+	jm := newJobsManager(namespace, spk, log.NewNopLogger(), config.Jobs{MaxUsers: 2})
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = jm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_search_concurrency_limit Maximum number of concurrent searches by type, from server/status/limits/search-concurrency API
+# TYPE splunk_exporter_search_concurrency_limit gauge
+splunk_exporter_search_concurrency_limit{type="auto_summary"} 5
+splunk_exporter_search_concurrency_limit{type="historical"} 26
+splunk_exporter_search_concurrency_limit{type="historical_scheduled"} 13
+splunk_exporter_search_concurrency_limit{type="realtime"} 26
+splunk_exporter_search_concurrency_limit{type="realtime_scheduled"} 13
+# HELP splunk_exporter_search_jobs Number of search jobs by type and state, from search/v2/jobs API
+# TYPE splunk_exporter_search_jobs gauge
+splunk_exporter_search_jobs{state="finished",type="adhoc"} 1
+splunk_exporter_search_jobs{state="finished",type="scheduled"} 1
+splunk_exporter_search_jobs{state="finished",type="summary"} 0
+splunk_exporter_search_jobs{state="queued",type="adhoc"} 0
+splunk_exporter_search_jobs{state="queued",type="scheduled"} 1
+splunk_exporter_search_jobs{state="queued",type="summary"} 0
+splunk_exporter_search_jobs{state="running",type="adhoc"} 2
+splunk_exporter_search_jobs{state="running",type="scheduled"} 0
+splunk_exporter_search_jobs{state="running",type="summary"} 1
+# HELP splunk_exporter_search_user_jobs Number of search jobs of a user by state, users over the configured limit are summed as "_other", from search/v2/jobs API
+# TYPE splunk_exporter_search_user_jobs gauge
+splunk_exporter_search_user_jobs{state="finished",user="_other"} 1
+splunk_exporter_search_user_jobs{state="finished",user="admin"} 1
+splunk_exporter_search_user_jobs{state="finished",user="nobody"} 0
+splunk_exporter_search_user_jobs{state="queued",user="_other"} 0
+splunk_exporter_search_user_jobs{state="queued",user="admin"} 0
+splunk_exporter_search_user_jobs{state="queued",user="nobody"} 1
+splunk_exporter_search_user_jobs{state="running",user="_other"} 1
+splunk_exporter_search_user_jobs{state="running",user="admin"} 1
+splunk_exporter_search_user_jobs{state="running",user="nobody"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_search_concurrency_limit",
+		"splunk_exporter_search_jobs",
+		"splunk_exporter_search_user_jobs",
+	))
+	// 3 apps in 3 states
+	assert.Equal(t, 9, testutil.CollectAndCount(c, "splunk_exporter_search_app_jobs"))
+	assert.True(t, ok)
+}
+
+func TestJobType(t *testing.T) {
+	assert.Equal(t, "adhoc", jobType("1714640400.101"))
+	assert.Equal(t, "scheduled", jobType("scheduler__admin__search__RMD5a1b2c3d4e5f6a7b8_at_1714640400_12"))
+	assert.Equal(t, "scheduled", jobType("rt_scheduler__admin__search__RMD5a1b2c3d4e5f6a7b8_at_1714640400_12"))
+	assert.Equal(t, "summary", jobType("_ACCELERATE_DM_Splunk_SA_CIM_Authentication_ACCELERATE_"))
+	assert.Equal(t, "summary", jobType("scheduler__nobody_c3BsdW5r__RMD5_summarize_1714640400"))
+}
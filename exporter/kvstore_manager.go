@@ -0,0 +1,169 @@
+package exporter
+
+import (
+	"slices"
+	"strings"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// kvStoreStatuses are the known statuses of the local KV store
+var kvStoreStatuses = []string{"starting", "ready", "failed", "shuttingdown"}
+
+// KVStoreManager collects KV store status and statistics, skipped on instances where KV store is disabled
+type KVStoreManager struct {
+	splunk                      *splunklib.Splunk // Splunk client
+	logger                      log.Logger
+	statusDescriptor            *prometheus.Desc
+	infoDescriptor              *prometheus.Desc
+	memberDescriptor            *prometheus.Desc
+	collectionSizeDescriptor    *prometheus.Desc
+	collectionStorageDescriptor *prometheus.Desc
+	collectionObjectsDescriptor *prometheus.Desc
+	operationsDescriptor        *prometheus.Desc
+}
+
+func newKVStoreManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *KVStoreManager {
+
+	level.Debug(logger).Log("msg", "Initiating KV store manager")
+
+	km := KVStoreManager{
+		splunk: spk,
+		logger: logger,
+		statusDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kvstore", "status"),
+			"Status of local KV store, 1 for the current one, from kvstore/status API",
+			[]string{"status"}, nil,
+		),
+		infoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kvstore", "info"),
+			"Storage engine and replication status of local KV store, from kvstore/status API",
+			[]string{"storage_engine", "replication_status"}, nil,
+		),
+		memberDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kvstore", "member_info"),
+			"Replication status of a KV store replica set member, from kvstore/status API",
+			[]string{"member", "replication_status"}, nil,
+		),
+		collectionSizeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kvstore", "collection_size_bytes"),
+			"Size of the data of a KV store collection, from server/introspection/kvstore/collectionstats API",
+			[]string{"app", "collection"}, nil,
+		),
+		collectionStorageDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kvstore", "collection_storage_bytes"),
+			"Storage allocated to a KV store collection, from server/introspection/kvstore/collectionstats API",
+			[]string{"app", "collection"}, nil,
+		),
+		collectionObjectsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kvstore", "collection_objects"),
+			"Number of objects in a KV store collection, from server/introspection/kvstore/collectionstats API",
+			[]string{"app", "collection"}, nil,
+		),
+		operationsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kvstore", "operations_total"),
+			"Operations run by KV store since its startup, from server/introspection/kvstore/serverstatus API",
+			[]string{"type"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating KV store manager")
+	return &km
+}
+
+func (km *KVStoreManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	enabled, err := hasServerRole(km.splunk, kvStoreRoles...)
+	if err != nil {
+		level.Error(km.logger).Log("msg", "failed to read server roles", "err", err)
+		return false
+	}
+	if !enabled {
+		level.Debug(km.logger).Log("msg", "KV store is disabled, skipping KV store measures")
+		return true
+	}
+
+	level.Info(km.logger).Log("msg", "Collecting KV store measures")
+	ret := true
+
+	status := splunklib.KVStoreStatus{}
+	if err := km.splunk.Client.Read(&status); err != nil {
+		level.Error(km.logger).Log("msg", "failed to read KV store status", "err", err)
+		ret = false
+	} else {
+		km.collectStatus(ch, &status)
+	}
+
+	collectionStats := splunklib.KVStoreCollectionStats{}
+	if err := km.splunk.Client.Read(&collectionStats); err != nil {
+		level.Error(km.logger).Log("msg", "failed to read KV store collections statistics", "err", err)
+		ret = false
+	} else if collections, err := collectionStats.Content.Collections(); err != nil {
+		level.Error(km.logger).Log("msg", "failed to decode KV store collections statistics", "err", err)
+		ret = false
+	} else {
+		km.collectCollections(ch, collections)
+	}
+
+	serverStatus := splunklib.KVStoreServerStatus{}
+	if err := km.splunk.Client.Read(&serverStatus); err != nil {
+		level.Error(km.logger).Log("msg", "failed to read KV store server status", "err", err)
+		ret = false
+	} else {
+		km.collectOperations(ch, &serverStatus)
+	}
+
+	level.Info(km.logger).Log("msg", "Done collecting KV store measures", "success", ret)
+	return ret
+}
+
+// collectStatus sends local status as a state set, and replication status of each member
+func (km *KVStoreManager) collectStatus(ch chan<- prometheus.Metric, status *splunklib.KVStoreStatus) {
+	current := status.Content.Current
+
+	statuses := kvStoreStatuses
+	if !slices.Contains(statuses, current.Status) {
+		statuses = append(slices.Clone(statuses), current.Status)
+	}
+	for _, s := range statuses {
+		ch <- prometheus.MustNewConstMetric(
+			km.statusDescriptor, prometheus.GaugeValue, boolToFloat(s == current.Status), s,
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		km.infoDescriptor, prometheus.GaugeValue, 1, current.StorageEngine, current.ReplicationStatus,
+	)
+
+	for _, m := range status.Content.Members {
+		ch <- prometheus.MustNewConstMetric(
+			km.memberDescriptor, prometheus.GaugeValue, 1, m.HostAndPort, m.ReplicationStatus,
+		)
+	}
+}
+
+// collectCollections sends size and number of objects of each collection
+func (km *KVStoreManager) collectCollections(ch chan<- prometheus.Metric, collections []splunklib.KVStoreCollectionStat) {
+	for _, c := range collections {
+		app, collection, _ := strings.Cut(c.NS, ".")
+		ch <- prometheus.MustNewConstMetric(
+			km.collectionSizeDescriptor, prometheus.GaugeValue, float64(c.Size), app, collection,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			km.collectionStorageDescriptor, prometheus.GaugeValue, float64(c.StorageSize), app, collection,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			km.collectionObjectsDescriptor, prometheus.GaugeValue, float64(c.Count), app, collection,
+		)
+	}
+}
+
+// collectOperations sends operation counters by type
+func (km *KVStoreManager) collectOperations(ch chan<- prometheus.Metric, status *splunklib.KVStoreServerStatus) {
+	for typ, count := range status.Content.Opcounters {
+		ch <- prometheus.MustNewConstMetric(
+			km.operationsDescriptor, prometheus.CounterValue, float64(count), typ,
+		)
+	}
+}
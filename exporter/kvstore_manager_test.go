@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKVStoreManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info":                                  "testdata/serverinfo.json",
+		"/services/kvstore/status":                               "testdata/kvstorestatus.json",
+		"/services/server/introspection/kvstore/collectionstats": "testdata/kvstorecollectionstats.json",
+		"/services/server/introspection/kvstore/serverstatus":    "testdata/kvstoreserverstatus.json",
+	})
+	km := newKVStoreManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = km.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_kvstore_collection_objects Number of objects in a KV store collection, from server/introspection/kvstore/collectionstats API
+# TYPE splunk_exporter_kvstore_collection_objects gauge
+splunk_exporter_kvstore_collection_objects{app="search",collection="my_lookup"} 1500
+splunk_exporter_kvstore_collection_objects{app="splunk_instrumentation",collection="telemetry"} 0
+# HELP splunk_exporter_kvstore_collection_size_bytes Size of the data of a KV store collection, from server/introspection/kvstore/collectionstats API
+# TYPE splunk_exporter_kvstore_collection_size_bytes gauge
+splunk_exporter_kvstore_collection_size_bytes{app="search",collection="my_lookup"} 204800
+splunk_exporter_kvstore_collection_size_bytes{app="splunk_instrumentation",collection="telemetry"} 0
+# HELP splunk_exporter_kvstore_info Storage engine and replication status of local KV store, from kvstore/status API
+# TYPE splunk_exporter_kvstore_info gauge
+splunk_exporter_kvstore_info{replication_status="KV store captain",storage_engine="wiredTiger"} 1
+# HELP splunk_exporter_kvstore_member_info Replication status of a KV store replica set member, from kvstore/status API
+# TYPE splunk_exporter_kvstore_member_info gauge
+splunk_exporter_kvstore_member_info{member="sh1.splunk.local:8191",replication_status="KV store captain"} 1
+splunk_exporter_kvstore_member_info{member="sh2.splunk.local:8191",replication_status="Non-captain KV store member"} 1
+# HELP splunk_exporter_kvstore_operations_total Operations run by KV store since its startup, from server/introspection/kvstore/serverstatus API
+# TYPE splunk_exporter_kvstore_operations_total counter
+splunk_exporter_kvstore_operations_total{type="command"} 1.234567e+06
+splunk_exporter_kvstore_operations_total{type="delete"} 120
+splunk_exporter_kvstore_operations_total{type="getmore"} 4567
+splunk_exporter_kvstore_operations_total{type="insert"} 35000
+splunk_exporter_kvstore_operations_total{type="query"} 980000
+splunk_exporter_kvstore_operations_total{type="update"} 2100
+# HELP splunk_exporter_kvstore_status Status of local KV store, 1 for the current one, from kvstore/status API
+# TYPE splunk_exporter_kvstore_status gauge
+splunk_exporter_kvstore_status{status="failed"} 0
+splunk_exporter_kvstore_status{status="ready"} 1
+splunk_exporter_kvstore_status{status="shuttingdown"} 0
+splunk_exporter_kvstore_status{status="starting"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_kvstore_collection_objects",
+		"splunk_exporter_kvstore_collection_size_bytes",
+		"splunk_exporter_kvstore_info",
+		"splunk_exporter_kvstore_member_info",
+		"splunk_exporter_kvstore_operations_total",
+		"splunk_exporter_kvstore_status",
+	))
+	assert.True(t, ok)
+}
+
+func TestKVStoreManager_Disabled(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info": "testdata/serverinfo-nokvstore.json",
+	})
+	km := newKVStoreManager(namespace, spk, log.NewNopLogger())
+
+	ch := make(chan prometheus.Metric, 100)
+	assert.True(t, km.CollectMeasures(ch))
+	assert.Empty(t, ch)
+}
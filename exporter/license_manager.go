@@ -0,0 +1,188 @@
+package exporter
+
+import (
+	"strings"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// licenseWindowCategory is the category of licenser messages raised once per day a stack exceeded its quota
+const licenseWindowCategory = "license_window"
+
+type LicenseManager struct {
+	splunk                  *splunklib.Splunk // Splunk client
+	logger                  log.Logger
+	poolQuotaDescriptor     *prometheus.Desc
+	poolUsedDescriptor      *prometheus.Desc
+	stackQuotaDescriptor    *prometheus.Desc
+	stackUsedDescriptor     *prometheus.Desc
+	usageQuotaDescriptor    *prometheus.Desc
+	usageUsedDescriptor     *prometheus.Desc
+	violationDaysDescriptor *prometheus.Desc
+	messagesDescriptor      *prometheus.Desc
+}
+
+func newLicenseManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *LicenseManager {
+
+	level.Debug(logger).Log("msg", "Initiating license manager")
+
+	lm := LicenseManager{
+		splunk: spk,
+		logger: logger,
+		poolQuotaDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "pool_quota_bytes"),
+			"Daily indexing quota of a license pool, from licenser/pools API",
+			[]string{"pool", "stack"}, nil,
+		),
+		poolUsedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "pool_used_bytes"),
+			"Bytes indexed today in a license pool, from licenser/pools API",
+			[]string{"pool", "stack"}, nil,
+		),
+		stackQuotaDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "stack_quota_bytes"),
+			"Daily indexing quota of a license stack, from licenser/stacks API",
+			[]string{"stack", "label", "type"}, nil,
+		),
+		stackUsedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "stack_used_bytes"),
+			"Bytes indexed today in all pools of a license stack, from licenser/pools API",
+			[]string{"stack"}, nil,
+		),
+		usageQuotaDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "quota_bytes"),
+			"Total daily indexing quota, from licenser/usage API",
+			nil, nil,
+		),
+		usageUsedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "used_bytes"),
+			"Bytes indexed today by all license peers, from licenser/usage API",
+			nil, nil,
+		),
+		violationDaysDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "violation_days"),
+			"Number of days a license stack exceeded its quota in the rolling window, from licenser/messages API",
+			[]string{"stack"}, nil,
+		),
+		messagesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "messages"),
+			"Number of active licensing messages, from licenser/messages API",
+			[]string{"category", "severity"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating license manager")
+	return &lm
+}
+
+func (lm *LicenseManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(lm.logger).Log("msg", "Collecting License measures")
+	ret := true
+
+	stacks := make([]splunklib.LicenserStack, 0)
+	if err := lm.splunk.ListAll(&stacks, nil); err != nil {
+		level.Error(lm.logger).Log("msg", "failed to list license stacks", "err", err)
+		ret = false
+	}
+
+	pools := make([]splunklib.LicenserPool, 0)
+	if err := lm.splunk.ListAll(&pools, nil); err != nil {
+		level.Error(lm.logger).Log("msg", "failed to list license pools", "err", err)
+		ret = false
+	}
+
+	usage := splunklib.LicenserUsage{}
+	if err := lm.splunk.Client.Read(&usage); err != nil {
+		level.Error(lm.logger).Log("msg", "failed to read license usage", "err", err)
+		ret = false
+	} else {
+		lm.collectUsage(ch, &usage)
+	}
+
+	messages := make([]splunklib.LicenserMessage, 0)
+	if err := lm.splunk.ListAll(&messages, nil); err != nil {
+		level.Error(lm.logger).Log("msg", "failed to list license messages", "err", err)
+		ret = false
+	}
+
+	lm.collectStacks(ch, stacks, pools)
+	lm.collectPools(ch, pools)
+	lm.collectMessages(ch, stacks, messages)
+
+	level.Info(lm.logger).Log("msg", "Done collecting License measures", "success", ret)
+	return ret
+}
+
+// collectUsage sends instance wide quota and usage
+func (lm *LicenseManager) collectUsage(ch chan<- prometheus.Metric, usage *splunklib.LicenserUsage) {
+	ch <- prometheus.MustNewConstMetric(
+		lm.usageQuotaDescriptor, prometheus.GaugeValue, usage.Content.Quota,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		lm.usageUsedDescriptor, prometheus.GaugeValue, usage.Content.PeersUsageBytes,
+	)
+}
+
+// collectStacks sends quota of each stack, and its usage computed from the pools it contains
+func (lm *LicenseManager) collectStacks(ch chan<- prometheus.Metric, stacks []splunklib.LicenserStack, pools []splunklib.LicenserPool) {
+	used := make(map[string]float64)
+	for _, p := range pools {
+		used[p.Content.StackID] += p.Content.UsedBytes
+	}
+
+	for _, s := range stacks {
+		ch <- prometheus.MustNewConstMetric(
+			lm.stackQuotaDescriptor, prometheus.GaugeValue, s.Content.Quota, s.ID.Title, s.Content.Label, s.Content.Type,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			lm.stackUsedDescriptor, prometheus.GaugeValue, used[s.ID.Title], s.ID.Title,
+		)
+	}
+}
+
+// collectPools sends quota and usage of each pool
+func (lm *LicenseManager) collectPools(ch chan<- prometheus.Metric, pools []splunklib.LicenserPool) {
+	for _, p := range pools {
+		ch <- prometheus.MustNewConstMetric(
+			lm.poolQuotaDescriptor, prometheus.GaugeValue, p.Content.EffectiveQuota, p.ID.Title, p.Content.StackID,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			lm.poolUsedDescriptor, prometheus.GaugeValue, p.Content.UsedBytes, p.ID.Title, p.Content.StackID,
+		)
+	}
+}
+
+// collectMessages counts active licensing messages, and days in violation for each stack
+// Splunk raises one "license_window" message per day a stack went over quota, they expire after the rolling window.
+func (lm *LicenseManager) collectMessages(ch chan<- prometheus.Metric, stacks []splunklib.LicenserStack, messages []splunklib.LicenserMessage) {
+	type messageKey struct {
+		category string
+		severity string
+	}
+	counts := make(map[messageKey]float64)
+	violations := make(map[string]float64)
+	for _, s := range stacks {
+		violations[s.ID.Title] = 0
+	}
+
+	for _, m := range messages {
+		counts[messageKey{m.Content.Category, strings.ToLower(m.Content.Severity)}]++
+		if m.Content.Category == licenseWindowCategory {
+			violations[m.Content.StackID]++
+		}
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			lm.messagesDescriptor, prometheus.GaugeValue, count, k.category, k.severity,
+		)
+	}
+	for stack, days := range violations {
+		ch <- prometheus.MustNewConstMetric(
+			lm.violationDaysDescriptor, prometheus.GaugeValue, days, stack,
+		)
+	}
+}
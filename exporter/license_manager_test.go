@@ -0,0 +1,91 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLicenseManager(t *testing.T) *LicenseManager {
+	return newLicenseManager(namespace, nil, log.NewNopLogger())
+}
+
+func TestLicenseStacksAndPools(t *testing.T) {
+	lm := newTestLicenseManager(t)
+	stacks := readTestEntries[splunklib.LicenserStack](t, "testdata/licenserstacks.json")
+	pools := readTestEntries[splunklib.LicenserPool](t, "testdata/licenserpools.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		lm.collectStacks(ch, stacks, pools)
+		lm.collectPools(ch, pools)
+	})
+
+	expected := `
+# HELP splunk_exporter_license_pool_quota_bytes Daily indexing quota of a license pool, from licenser/pools API
+# TYPE splunk_exporter_license_pool_quota_bytes gauge
+splunk_exporter_license_pool_quota_bytes{pool="auto_generated_pool_download-trial",stack="download-trial"} 5.24288e+08
+splunk_exporter_license_pool_quota_bytes{pool="auto_generated_pool_enterprise",stack="enterprise"} 1.073741824e+11
+splunk_exporter_license_pool_quota_bytes{pool="security",stack="enterprise"} 2.147483648e+10
+# HELP splunk_exporter_license_pool_used_bytes Bytes indexed today in a license pool, from licenser/pools API
+# TYPE splunk_exporter_license_pool_used_bytes gauge
+splunk_exporter_license_pool_used_bytes{pool="auto_generated_pool_download-trial",stack="download-trial"} 0
+splunk_exporter_license_pool_used_bytes{pool="auto_generated_pool_enterprise",stack="enterprise"} 6.1203754931e+10
+splunk_exporter_license_pool_used_bytes{pool="security",stack="enterprise"} 2.3622320128e+10
+# HELP splunk_exporter_license_stack_quota_bytes Daily indexing quota of a license stack, from licenser/stacks API
+# TYPE splunk_exporter_license_stack_quota_bytes gauge
+splunk_exporter_license_stack_quota_bytes{label="Splunk Enterprise Download Trial",stack="download-trial",type="download-trial"} 5.24288e+08
+splunk_exporter_license_stack_quota_bytes{label="Splunk Enterprise",stack="enterprise",type="enterprise"} 1.073741824e+11
+# HELP splunk_exporter_license_stack_used_bytes Bytes indexed today in all pools of a license stack, from licenser/pools API
+# TYPE splunk_exporter_license_stack_used_bytes gauge
+splunk_exporter_license_stack_used_bytes{stack="download-trial"} 0
+splunk_exporter_license_stack_used_bytes{stack="enterprise"} 8.4826075059e+10
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestLicenseUsage(t *testing.T) {
+	lm := newTestLicenseManager(t)
+	usage := readTestEntries[splunklib.LicenserUsage](t, "testdata/licenserusage.json")
+	assert.Len(t, usage, 1)
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		lm.collectUsage(ch, &usage[0])
+	})
+
+	expected := `
+# HELP splunk_exporter_license_quota_bytes Total daily indexing quota, from licenser/usage API
+# TYPE splunk_exporter_license_quota_bytes gauge
+splunk_exporter_license_quota_bytes 1.078984704e+11
+# HELP splunk_exporter_license_used_bytes Bytes indexed today by all license peers, from licenser/usage API
+# TYPE splunk_exporter_license_used_bytes gauge
+splunk_exporter_license_used_bytes 8.4826075059e+10
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestLicenseMessages(t *testing.T) {
+	lm := newTestLicenseManager(t)
+	stacks := readTestEntries[splunklib.LicenserStack](t, "testdata/licenserstacks.json")
+	messages := readTestEntries[splunklib.LicenserMessage](t, "testdata/licensermessages.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		lm.collectMessages(ch, stacks, messages)
+	})
+
+	expected := `
+# HELP splunk_exporter_license_messages Number of active licensing messages, from licenser/messages API
+# TYPE splunk_exporter_license_messages gauge
+splunk_exporter_license_messages{category="license_window",severity="warn"} 2
+splunk_exporter_license_messages{category="pool_over_quota",severity="error"} 1
+# HELP splunk_exporter_license_violation_days Number of days a license stack exceeded its quota in the rolling window, from licenser/messages API
+# TYPE splunk_exporter_license_violation_days gauge
+splunk_exporter_license_violation_days{stack="download-trial"} 0
+splunk_exporter_license_violation_days{stack="enterprise"} 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
@@ -0,0 +1,114 @@
+package exporter
+
+import (
+	"path"
+	"strings"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// messageSeverities are the known severities of Splunk messages
+var messageSeverities = []string{"info", "warn", "error"}
+
+// MessagesManager collects messages shown in the bulletin menu of Splunk Web
+type MessagesManager struct {
+	splunk             *splunklib.Splunk // Splunk client
+	logger             log.Logger
+	allow              []string // name patterns of messages to export, all when empty
+	deny               []string // name patterns of messages to ignore
+	messagesDescriptor *prometheus.Desc
+	infoDescriptor     *prometheus.Desc
+}
+
+func newMessagesManager(namespace string, spk *splunklib.Splunk, logger log.Logger, conf config.Messages) *MessagesManager {
+
+	level.Debug(logger).Log("msg", "Initiating messages manager")
+
+	for _, p := range append(append([]string{}, conf.Allow...), conf.Deny...) {
+		if _, err := path.Match(p, ""); err != nil {
+			level.Warn(logger).Log("msg", "invalid message name pattern, it will never match", "pattern", p, "err", err)
+		}
+	}
+
+	mm := MessagesManager{
+		splunk: spk,
+		logger: logger,
+		allow:  conf.Allow,
+		deny:   conf.Deny,
+		messagesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "messages"),
+			"Number of active Splunk messages by severity, from messages API",
+			[]string{"severity"}, nil,
+		),
+		infoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "message", "info"),
+			"Active Splunk message, from messages API",
+			[]string{"name", "severity"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating messages manager")
+	return &mm
+}
+
+func (mm *MessagesManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(mm.logger).Log("msg", "Collecting Messages measures")
+
+	messages := make([]splunklib.Message, 0)
+	if err := mm.splunk.ListAll(&messages, nil); err != nil {
+		level.Error(mm.logger).Log("msg", "failed to list messages", "err", err)
+		return false
+	}
+	mm.collectMessages(ch, messages)
+
+	level.Info(mm.logger).Log("msg", "Done collecting Messages measures")
+	return true
+}
+
+// collectMessages sends each message allowed by configuration, and their count by severity
+func (mm *MessagesManager) collectMessages(ch chan<- prometheus.Metric, messages []splunklib.Message) {
+	counts := make(map[string]float64, len(messageSeverities))
+	for _, s := range messageSeverities {
+		counts[s] = 0
+	}
+
+	for _, m := range messages {
+		name := m.ID.Title
+		if !mm.isExported(name) {
+			continue
+		}
+		severity := strings.ToLower(m.Content.Severity)
+		counts[severity]++
+		ch <- prometheus.MustNewConstMetric(
+			mm.infoDescriptor, prometheus.GaugeValue, 1, name, severity,
+		)
+	}
+
+	for severity, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			mm.messagesDescriptor, prometheus.GaugeValue, count, severity,
+		)
+	}
+}
+
+// isExported tells whether a message name is allowed and not denied by configuration
+func (mm *MessagesManager) isExported(name string) bool {
+	if len(mm.allow) > 0 && !matchesAny(mm.allow, name) {
+		return false
+	}
+	return !matchesAny(mm.deny, name)
+}
+
+// matchesAny tells whether name matches one of the shell patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
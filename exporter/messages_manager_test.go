@@ -0,0 +1,62 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessages(t *testing.T) {
+	mm := newMessagesManager(namespace, nil, log.NewNopLogger(), config.Messages{})
+	messages := readTestEntries[splunklib.Message](t, "testdata/messages.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		mm.collectMessages(ch, messages)
+	})
+
+	expected := `
+# HELP splunk_exporter_message_info Active Splunk message, from messages API
+# TYPE splunk_exporter_message_info gauge
+splunk_exporter_message_info{name="LM_LICENSE_ALERTS_STATUS",severity="warn"} 1
+splunk_exporter_message_info{name="LM_LICENSE_EXPIRED",severity="error"} 1
+splunk_exporter_message_info{name="SEARCHFACTOR_NOT_MET",severity="warn"} 1
+splunk_exporter_message_info{name="restart_required",severity="info"} 1
+# HELP splunk_exporter_messages Number of active Splunk messages by severity, from messages API
+# TYPE splunk_exporter_messages gauge
+splunk_exporter_messages{severity="error"} 1
+splunk_exporter_messages{severity="info"} 1
+splunk_exporter_messages{severity="warn"} 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestMessages_AllowDeny(t *testing.T) {
+	mm := newMessagesManager(namespace, nil, log.NewNopLogger(), config.Messages{
+		Allow: []string{"LM_*", "restart_required"},
+		Deny:  []string{"LM_LICENSE_EXPIRED"},
+	})
+	messages := readTestEntries[splunklib.Message](t, "testdata/messages.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		mm.collectMessages(ch, messages)
+	})
+
+	expected := `
+# HELP splunk_exporter_message_info Active Splunk message, from messages API
+# TYPE splunk_exporter_message_info gauge
+splunk_exporter_message_info{name="LM_LICENSE_ALERTS_STATUS",severity="warn"} 1
+splunk_exporter_message_info{name="restart_required",severity="info"} 1
+# HELP splunk_exporter_messages Number of active Splunk messages by severity, from messages API
+# TYPE splunk_exporter_messages gauge
+splunk_exporter_messages{severity="error"} 0
+splunk_exporter_messages{severity="info"} 1
+splunk_exporter_messages{severity="warn"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
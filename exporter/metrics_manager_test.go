@@ -119,9 +119,8 @@ func TestMetricsManager_ConcurrentAccess(t *testing.T) {
 	}))
 	defer server.Close()
 
-	_, w, _ := os.Pipe()
-	defer w.Close()
-	logger := log.NewJSONLogger(w)
+	// a no-op logger: concurrent writes to an unread pipe block once its buffer is full
+	logger := log.NewNopLogger()
 
 	client := &splunkclient.Client{
 		URL:           server.URL,
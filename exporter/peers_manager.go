@@ -0,0 +1,260 @@
+package exporter
+
+import (
+	"slices"
+	"sync"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// searchPeerStatuses are the known statuses of a search peer
+	searchPeerStatuses = []string{"Up", "Down", "Sick"}
+	// bundleReplicationStatuses are the known statuses of knowledge bundle replication to a search peer
+	bundleReplicationStatuses = []string{"Successful", "Failed", "In Progress", "Initial"}
+)
+
+// PeersManager collects search peers of the scraped search head, and the replication of knowledge bundles to them
+type PeersManager struct {
+	splunk                      *splunklib.Splunk // Splunk client
+	logger                      log.Logger
+	peersDescriptor             *prometheus.Desc
+	peerStatusDescriptor        *prometheus.Desc
+	peerReplicationDescriptor   *prometheus.Desc
+	peerInfoDescriptor          *prometheus.Desc
+	peerHeartbeatDescriptor     *prometheus.Desc
+	bundleReplicationDescriptor *prometheus.Desc
+	peerBundleDescriptor        *prometheus.Desc
+	peerFailuresDescriptor      *prometheus.Desc
+	bundleSizeDescriptor        *prometheus.Desc
+	bundleTimestampDescriptor   *prometheus.Desc
+
+	// replication failures are derived from successive scrapes
+	failuresMu          sync.Mutex        // guards lastReplication and replicationFailures
+	lastReplication     map[string]string // replication status of each peer during the last scrape
+	replicationFailures map[string]float64
+}
+
+func newPeersManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *PeersManager {
+
+	level.Debug(logger).Log("msg", "Initiating peers manager")
+
+	pm := PeersManager{
+		splunk: spk,
+		logger: logger,
+		peersDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peers"),
+			"Number of enabled search peers by status, from search/distributed/peers API",
+			[]string{"status"}, nil,
+		),
+		peerStatusDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peer_status"),
+			"Status of a search peer, 1 for its current status, from search/distributed/peers API",
+			[]string{"peer", "status"}, nil,
+		),
+		peerReplicationDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peer_bundle_replication_status"),
+			"Status of knowledge bundle replication to a search peer, 1 for its current status, from search/distributed/peers API",
+			[]string{"peer", "status"}, nil,
+		),
+		peerInfoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peer_info"),
+			"Identity of a search peer, from search/distributed/peers API",
+			[]string{"peer", "name", "version", "guid"}, nil,
+		),
+		peerHeartbeatDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peer_last_heartbeat_timestamp_seconds"),
+			"Last heartbeat received from a search peer, from search/distributed/peers API",
+			[]string{"peer"}, nil,
+		),
+		bundleReplicationDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peers_bundle_replication"),
+			"Number of enabled search peers by status of knowledge bundle replication, from search/distributed/peers API",
+			[]string{"status"}, nil,
+		),
+		peerBundleDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peer_bundle_created_timestamp_seconds"),
+			"Creation time of the newest knowledge bundle a search peer has, not the time it was replicated, from search/distributed/peers API",
+			[]string{"peer"}, nil,
+		),
+		peerFailuresDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "peer_bundle_replication_failures_total"),
+			"Number of times knowledge bundle replication to a search peer became Failed, seen by the exporter since it first listed the peer",
+			[]string{"peer"}, nil,
+		),
+		bundleSizeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "bundle_size_bytes"),
+			"Size of the latest knowledge bundle of the search head, from search/distributed/bundle-replication-files API",
+			nil, nil,
+		),
+		bundleTimestampDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "search", "bundle_timestamp_seconds"),
+			"Creation time of the latest knowledge bundle of the search head, from search/distributed/bundle-replication-files API",
+			nil, nil,
+		),
+		lastReplication:     make(map[string]string),
+		replicationFailures: make(map[string]float64),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating peers manager")
+	return &pm
+}
+
+func (pm *PeersManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(pm.logger).Log("msg", "Collecting Peers measures")
+	ret := true
+
+	peers := make([]splunklib.DistributedPeer, 0)
+	if err := pm.splunk.ListAll(&peers, nil); err != nil {
+		level.Error(pm.logger).Log("msg", "failed to list search peers", "err", err)
+		ret = false
+	} else {
+		if len(peers) == 0 {
+			level.Debug(pm.logger).Log("msg", "No search peer, skipping search peers measures")
+		} else {
+			pm.collectPeers(ch, peers)
+		}
+		// peers no longer listed are forgotten even when none is left
+		pm.collectReplicationFailures(ch, peers)
+	}
+
+	// the knowledge bundle of the search head is built even before peers are added
+	bundles := make([]splunklib.BundleReplicationFile, 0)
+	if err := pm.splunk.ListAll(&bundles, nil); err != nil {
+		level.Error(pm.logger).Log("msg", "failed to list knowledge bundles", "err", err)
+		ret = false
+	} else {
+		pm.collectBundles(ch, bundles)
+	}
+
+	level.Info(pm.logger).Log("msg", "Done collecting Peers measures", "success", ret)
+	return ret
+}
+
+// collectPeers sends status, bundle replication and identity of each enabled peer, and counts peers by status
+func (pm *PeersManager) collectPeers(ch chan<- prometheus.Metric, peers []splunklib.DistributedPeer) {
+	statusCounts := make(map[string]float64, len(searchPeerStatuses))
+	for _, s := range searchPeerStatuses {
+		statusCounts[s] = 0
+	}
+	replicationCounts := make(map[string]float64, len(bundleReplicationStatuses))
+	for _, s := range bundleReplicationStatuses {
+		replicationCounts[s] = 0
+	}
+
+	for _, p := range peers {
+		c := p.Content
+		if c.Disabled {
+			continue
+		}
+		name := splunklib.EntryName(p.ID)
+		statusCounts[c.Status]++
+		replicationCounts[c.ReplicationStatus]++
+
+		statuses := searchPeerStatuses
+		if !slices.Contains(statuses, c.Status) {
+			statuses = append(slices.Clone(statuses), c.Status)
+		}
+		for _, s := range statuses {
+			ch <- prometheus.MustNewConstMetric(
+				pm.peerStatusDescriptor, prometheus.GaugeValue, boolToFloat(c.Status == s), name, s,
+			)
+		}
+		statuses = bundleReplicationStatuses
+		if !slices.Contains(statuses, c.ReplicationStatus) {
+			statuses = append(slices.Clone(statuses), c.ReplicationStatus)
+		}
+		for _, s := range statuses {
+			ch <- prometheus.MustNewConstMetric(
+				pm.peerReplicationDescriptor, prometheus.GaugeValue, boolToFloat(c.ReplicationStatus == s), name, s,
+			)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			pm.peerInfoDescriptor, prometheus.GaugeValue, 1, name, c.PeerName, c.Version, c.GUID,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			pm.peerHeartbeatDescriptor, prometheus.GaugeValue, float64(c.LastHeartbeat), name,
+		)
+		if len(c.BundleVersions) > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				pm.peerBundleDescriptor, prometheus.GaugeValue, float64(slices.Max(c.BundleVersions)), name,
+			)
+		}
+	}
+
+	for status, count := range statusCounts {
+		ch <- prometheus.MustNewConstMetric(
+			pm.peersDescriptor, prometheus.GaugeValue, count, status,
+		)
+	}
+	for status, count := range replicationCounts {
+		ch <- prometheus.MustNewConstMetric(
+			pm.bundleReplicationDescriptor, prometheus.GaugeValue, count, status,
+		)
+	}
+}
+
+// collectReplicationFailures counts peers whose bundle replication became Failed since the previous scrape
+// a peer starts at 0 when first listed, even if already failing, so that rate() and increase() see its first failure.
+// Peers no longer listed, or disabled, are forgotten.
+func (pm *PeersManager) collectReplicationFailures(ch chan<- prometheus.Metric, peers []splunklib.DistributedPeer) {
+	pm.failuresMu.Lock()
+	defer pm.failuresMu.Unlock()
+
+	listed := make(map[string]struct{}, len(peers))
+	for _, p := range peers {
+		if p.Content.Disabled {
+			continue
+		}
+		name := splunklib.EntryName(p.ID)
+		listed[name] = struct{}{}
+		status := p.Content.ReplicationStatus
+		last, known := pm.lastReplication[name]
+		pm.lastReplication[name] = status
+		if !known {
+			pm.replicationFailures[name] = 0
+			continue
+		}
+		if status == "Failed" && last != "Failed" {
+			level.Info(pm.logger).Log("msg", "Knowledge bundle replication to search peer failed", "peer", name)
+			pm.replicationFailures[name]++
+		}
+	}
+	for name := range pm.lastReplication {
+		if _, ok := listed[name]; !ok {
+			delete(pm.lastReplication, name)
+			delete(pm.replicationFailures, name)
+		}
+	}
+
+	for name, failures := range pm.replicationFailures {
+		ch <- prometheus.MustNewConstMetric(
+			pm.peerFailuresDescriptor, prometheus.CounterValue, failures, name,
+		)
+	}
+}
+
+// collectBundles sends size and creation time of the latest knowledge bundle
+func (pm *PeersManager) collectBundles(ch chan<- prometheus.Metric, bundles []splunklib.BundleReplicationFile) {
+	if len(bundles) == 0 {
+		return
+	}
+	latest := slices.MaxFunc(bundles, func(a, b splunklib.BundleReplicationFile) int {
+		if a.Content.Timestamp < b.Content.Timestamp {
+			return -1
+		}
+		if a.Content.Timestamp > b.Content.Timestamp {
+			return 1
+		}
+		return 0
+	})
+	ch <- prometheus.MustNewConstMetric(
+		pm.bundleSizeDescriptor, prometheus.GaugeValue, float64(latest.Content.Size),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		pm.bundleTimestampDescriptor, prometheus.GaugeValue, float64(latest.Content.Timestamp),
+	)
+}
@@ -0,0 +1,142 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeersManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/search/distributed/peers":                    "testdata/searchdistributedpeers.json",
+		"/services/search/distributed/bundle-replication-files": "testdata/searchdistributedbundlereplicationfiles.json",
+	})
+	pm := newPeersManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = pm.CollectMeasures(ch)
+	})
+
+	// the disabled peer idx4 is ignored, idx3 already failing when first listed starts at 0
+	expected := `
+# HELP splunk_exporter_search_bundle_size_bytes Size of the latest knowledge bundle of the search head, from search/distributed/bundle-replication-files API
+# TYPE splunk_exporter_search_bundle_size_bytes gauge
+splunk_exporter_search_bundle_size_bytes 5.24288e+07
+# HELP splunk_exporter_search_bundle_timestamp_seconds Creation time of the latest knowledge bundle of the search head, from search/distributed/bundle-replication-files API
+# TYPE splunk_exporter_search_bundle_timestamp_seconds gauge
+splunk_exporter_search_bundle_timestamp_seconds 1.71464e+09
+# HELP splunk_exporter_search_peer_bundle_created_timestamp_seconds Creation time of the newest knowledge bundle a search peer has, not the time it was replicated, from search/distributed/peers API
+# TYPE splunk_exporter_search_peer_bundle_created_timestamp_seconds gauge
+splunk_exporter_search_peer_bundle_created_timestamp_seconds{peer="idx1:8089"} 1.71464e+09
+splunk_exporter_search_peer_bundle_created_timestamp_seconds{peer="idx2:8089"} 1.71464e+09
+splunk_exporter_search_peer_bundle_created_timestamp_seconds{peer="idx3:8089"} 1.7146364e+09
+# HELP splunk_exporter_search_peer_bundle_replication_failures_total Number of times knowledge bundle replication to a search peer became Failed, seen by the exporter since it first listed the peer
+# TYPE splunk_exporter_search_peer_bundle_replication_failures_total counter
+splunk_exporter_search_peer_bundle_replication_failures_total{peer="idx1:8089"} 0
+splunk_exporter_search_peer_bundle_replication_failures_total{peer="idx2:8089"} 0
+splunk_exporter_search_peer_bundle_replication_failures_total{peer="idx3:8089"} 0
+# HELP splunk_exporter_search_peer_info Identity of a search peer, from search/distributed/peers API
+# TYPE splunk_exporter_search_peer_info gauge
+splunk_exporter_search_peer_info{guid="3A8F1C2E-5B6D-4E7F-8A9B-0C1D2E3F4A5B",name="idx1",peer="idx1:8089",version="9.2.1"} 1
+splunk_exporter_search_peer_info{guid="4B9A2D3F-6C7E-4F80-9BAC-1D2E3F4A5B6C",name="idx2",peer="idx2:8089",version="9.2.1"} 1
+splunk_exporter_search_peer_info{guid="5CAB3E4A-7D8F-4091-ACBD-2E3F4A5B6C7D",name="idx3",peer="idx3:8089",version="9.1.4"} 1
+# HELP splunk_exporter_search_peer_last_heartbeat_timestamp_seconds Last heartbeat received from a search peer, from search/distributed/peers API
+# TYPE splunk_exporter_search_peer_last_heartbeat_timestamp_seconds gauge
+splunk_exporter_search_peer_last_heartbeat_timestamp_seconds{peer="idx1:8089"} 1.7146404e+09
+splunk_exporter_search_peer_last_heartbeat_timestamp_seconds{peer="idx2:8089"} 1.714640395e+09
+splunk_exporter_search_peer_last_heartbeat_timestamp_seconds{peer="idx3:8089"} 1.71463e+09
+# HELP splunk_exporter_search_peers Number of enabled search peers by status, from search/distributed/peers API
+# TYPE splunk_exporter_search_peers gauge
+splunk_exporter_search_peers{status="Down"} 1
+splunk_exporter_search_peers{status="Sick"} 0
+splunk_exporter_search_peers{status="Up"} 2
+# HELP splunk_exporter_search_peers_bundle_replication Number of enabled search peers by status of knowledge bundle replication, from search/distributed/peers API
+# TYPE splunk_exporter_search_peers_bundle_replication gauge
+splunk_exporter_search_peers_bundle_replication{status="Failed"} 1
+splunk_exporter_search_peers_bundle_replication{status="In Progress"} 0
+splunk_exporter_search_peers_bundle_replication{status="Initial"} 0
+splunk_exporter_search_peers_bundle_replication{status="Successful"} 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_search_bundle_size_bytes",
+		"splunk_exporter_search_bundle_timestamp_seconds",
+		"splunk_exporter_search_peer_bundle_created_timestamp_seconds",
+		"splunk_exporter_search_peer_bundle_replication_failures_total",
+		"splunk_exporter_search_peer_info",
+		"splunk_exporter_search_peer_last_heartbeat_timestamp_seconds",
+		"splunk_exporter_search_peers",
+		"splunk_exporter_search_peers_bundle_replication",
+	))
+	assert.True(t, ok)
+	assert.Equal(t, 3*len(searchPeerStatuses), testutil.CollectAndCount(c, "splunk_exporter_search_peer_status"))
+	assert.Equal(t, 3*len(bundleReplicationStatuses), testutil.CollectAndCount(c, "splunk_exporter_search_peer_bundle_replication_status"))
+}
+
+func TestPeersManager_ReplicationFailures(t *testing.T) {
+	pm := newPeersManager(namespace, nil, log.NewNopLogger())
+	peers := readTestEntries[splunklib.DistributedPeer](t, "testdata/searchdistributedpeers.json")
+	peer := peers[0]
+
+	collect := func(peers []splunklib.DistributedPeer, expected string) {
+		c := testCollector(func(ch chan<- prometheus.Metric) {
+			pm.collectReplicationFailures(ch, peers)
+		})
+		assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+	}
+	failures := func(count string) string {
+		return `
+# HELP splunk_exporter_search_peer_bundle_replication_failures_total Number of times knowledge bundle replication to a search peer became Failed, seen by the exporter since it first listed the peer
+# TYPE splunk_exporter_search_peer_bundle_replication_failures_total counter
+splunk_exporter_search_peer_bundle_replication_failures_total{peer="idx1:8089"} ` + count + "\n"
+	}
+
+	// already failing when first listed, the failure happened before
+	for _, step := range []struct{ status, failures string }{
+		{"Failed", "0"},
+		{"Successful", "0"},
+		{"Failed", "1"},
+		{"Failed", "1"},
+		{"In Progress", "1"},
+		{"Failed", "2"},
+		{"Successful", "2"},
+	} {
+		peer.Content.ReplicationStatus = step.status
+		collect([]splunklib.DistributedPeer{peer}, failures(step.failures))
+	}
+
+	// a removed peer is forgotten, it starts again at 0 if added back
+	collect(nil, "")
+	peer.Content.ReplicationStatus = "Failed"
+	collect([]splunklib.DistributedPeer{peer}, failures("0"))
+}
+
+func TestPeersManager_NoPeer(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/search/distributed/peers":                    "testdata/searchdistributedpeers-none.json",
+		"/services/search/distributed/bundle-replication-files": "testdata/searchdistributedbundlereplicationfiles.json",
+	})
+	pm := newPeersManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = pm.CollectMeasures(ch)
+	})
+
+	// the knowledge bundle is exported without peers
+	expected := `
+# HELP splunk_exporter_search_bundle_size_bytes Size of the latest knowledge bundle of the search head, from search/distributed/bundle-replication-files API
+# TYPE splunk_exporter_search_bundle_size_bytes gauge
+splunk_exporter_search_bundle_size_bytes 5.24288e+07
+# HELP splunk_exporter_search_bundle_timestamp_seconds Creation time of the latest knowledge bundle of the search head, from search/distributed/bundle-replication-files API
+# TYPE splunk_exporter_search_bundle_timestamp_seconds gauge
+splunk_exporter_search_bundle_timestamp_seconds 1.71464e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+	assert.True(t, ok)
+}
@@ -0,0 +1,96 @@
+package exporter
+
+import (
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueuesManager collects fill level of processing queues (parsing, aggregation, typing, indexing, tcpout…)
+type QueuesManager struct {
+	splunk                 *splunklib.Splunk // Splunk client
+	logger                 log.Logger
+	currentSizeDescriptor  *prometheus.Desc
+	currentBytesDescriptor *prometheus.Desc
+	largestSizeDescriptor  *prometheus.Desc
+	maxBytesDescriptor     *prometheus.Desc
+	fillRatioDescriptor    *prometheus.Desc
+}
+
+func newQueuesManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *QueuesManager {
+
+	level.Debug(logger).Log("msg", "Initiating queues manager")
+
+	qm := QueuesManager{
+		splunk: spk,
+		logger: logger,
+		currentSizeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "current_size"),
+			"Number of events in a processing queue, from server/introspection/queues API",
+			[]string{"queue"}, nil,
+		),
+		currentBytesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "current_size_bytes"),
+			"Size of events in a processing queue, from server/introspection/queues API",
+			[]string{"queue"}, nil,
+		),
+		largestSizeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "largest_size"),
+			"Largest number of events seen in a processing queue since startup, from server/introspection/queues API",
+			[]string{"queue"}, nil,
+		),
+		maxBytesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "max_size_bytes"),
+			"Capacity of a processing queue, from server/introspection/queues API",
+			[]string{"queue"}, nil,
+		),
+		fillRatioDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "fill_ratio"),
+			"Fill ratio of a processing queue, between 0 and 1, from server/introspection/queues API",
+			[]string{"queue"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating queues manager")
+	return &qm
+}
+
+func (qm *QueuesManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(qm.logger).Log("msg", "Collecting Queues measures")
+
+	queues := make([]splunklib.ServerIntrospectionQueue, 0)
+	if err := qm.splunk.ListAll(&queues, nil); err != nil {
+		level.Error(qm.logger).Log("msg", "failed to list queues", "err", err)
+		return false
+	}
+	qm.collectQueues(ch, queues)
+
+	level.Info(qm.logger).Log("msg", "Done collecting Queues measures")
+	return true
+}
+
+// collectQueues sends size and fill ratio of each queue
+// fill ratio is not sent for queues without a capacity.
+func (qm *QueuesManager) collectQueues(ch chan<- prometheus.Metric, queues []splunklib.ServerIntrospectionQueue) {
+	for _, q := range queues {
+		name := q.ID.Title
+		ch <- prometheus.MustNewConstMetric(
+			qm.currentSizeDescriptor, prometheus.GaugeValue, float64(q.Content.CurrentSize), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			qm.currentBytesDescriptor, prometheus.GaugeValue, float64(q.Content.CurrentSizeBytes), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			qm.largestSizeDescriptor, prometheus.GaugeValue, float64(q.Content.LargestSize), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			qm.maxBytesDescriptor, prometheus.GaugeValue, float64(q.Content.MaxSizeBytes), name,
+		)
+		if q.Content.MaxSizeBytes > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				qm.fillRatioDescriptor, prometheus.GaugeValue, float64(q.Content.CurrentSizeBytes/q.Content.MaxSizeBytes), name,
+			)
+		}
+	}
+}
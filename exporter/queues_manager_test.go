@@ -0,0 +1,44 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueues(t *testing.T) {
+	qm := newQueuesManager(namespace, nil, log.NewNopLogger())
+	queues := readTestEntries[splunklib.ServerIntrospectionQueue](t, "testdata/serverintrospectionqueues.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		qm.collectQueues(ch, queues)
+	})
+
+	expected := `
+# HELP splunk_exporter_queue_fill_ratio Fill ratio of a processing queue, between 0 and 1, from server/introspection/queues API
+# TYPE splunk_exporter_queue_fill_ratio gauge
+splunk_exporter_queue_fill_ratio{queue="aggQueue"} 0.3
+splunk_exporter_queue_fill_ratio{queue="indexQueue"} 0.975
+splunk_exporter_queue_fill_ratio{queue="nullQueue"} 0
+splunk_exporter_queue_fill_ratio{queue="parsingQueue"} 0.010416666666666666
+splunk_exporter_queue_fill_ratio{queue="typingQueue"} 0
+# HELP splunk_exporter_queue_max_size_bytes Capacity of a processing queue, from server/introspection/queues API
+# TYPE splunk_exporter_queue_max_size_bytes gauge
+splunk_exporter_queue_max_size_bytes{queue="aggQueue"} 1.048576e+07
+splunk_exporter_queue_max_size_bytes{queue="indexQueue"} 1.048576e+07
+splunk_exporter_queue_max_size_bytes{queue="nullQueue"} 512000
+splunk_exporter_queue_max_size_bytes{queue="parsingQueue"} 6.291456e+06
+splunk_exporter_queue_max_size_bytes{queue="tcpout_primary_indexers"} 0
+splunk_exporter_queue_max_size_bytes{queue="typingQueue"} 512000
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_queue_fill_ratio",
+		"splunk_exporter_queue_max_size_bytes",
+	))
+	assert.Equal(t, len(queues), testutil.CollectAndCount(c, "splunk_exporter_queue_current_size"))
+}
@@ -0,0 +1,29 @@
+package exporter
+
+import (
+	"slices"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+)
+
+// Server roles as reported by server/info, older Splunk versions use the "master" naming.
+var (
+	clusterManagerRoles   = []string{"cluster_manager", "cluster_master"}
+	shcMemberRoles        = []string{"shc_member", "shc_captain"}
+	deploymentServerRoles = []string{"deployment_server"}
+	kvStoreRoles          = []string{"kv_store"} // only held while KV store is enabled
+)
+
+// hasServerRole tells whether the Splunk instance holds one of the given roles, according to server/info
+func hasServerRole(spk *splunklib.Splunk, roles ...string) (bool, error) {
+	info := splunklib.ServerInfo{}
+	if err := spk.Client.Read(&info); err != nil {
+		return false, err
+	}
+	for _, r := range info.Content.ServerRoles {
+		if slices.Contains(roles, r) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
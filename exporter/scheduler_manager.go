@@ -0,0 +1,194 @@
+package exporter
+
+import (
+	"cmp"
+	"net/url"
+	"slices"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultMaxSearches is the number of saved searches exported when not configured
+const defaultMaxSearches = 500
+
+// nextRunWindow is how far ahead the scheduled times of saved searches are listed
+// searches not dispatched in this window have no next run.
+const nextRunWindow = "+1d"
+
+// SchedulerManager collects scheduled searches and the outcome of their runs
+type SchedulerManager struct {
+	splunk               *splunklib.Splunk // Splunk client
+	logger               log.Logger
+	max                  int // maximum number of saved searches exported
+	scheduledDescriptor  *prometheus.Desc
+	searchesDescriptor   *prometheus.Desc
+	runsDescriptor       *prometheus.Desc
+	runTimeDescriptor    *prometheus.Desc
+	nextRunDescriptor    *prometheus.Desc
+	searchInfoDescriptor *prometheus.Desc
+}
+
+func newSchedulerManager(namespace string, spk *splunklib.Splunk, logger log.Logger, conf config.Scheduler) *SchedulerManager {
+
+	level.Debug(logger).Log("msg", "Initiating scheduler manager")
+
+	max := conf.MaxSearches
+	if max <= 0 {
+		max = defaultMaxSearches
+	}
+
+	sm := SchedulerManager{
+		splunk: spk,
+		logger: logger,
+		max:    max,
+		scheduledDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "scheduler", "scheduled_searches"),
+			"Number of enabled scheduled searches in an app, from saved/searches API",
+			[]string{"app"}, nil,
+		),
+		searchesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "scheduler", "searches_run"),
+			"Number of saved searches run by the scheduler in the last hour, including those over the exported limit, from scheduler logs",
+			nil, nil,
+		),
+		runsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "scheduler", "search_runs"),
+			"Number of runs of a scheduled search in the last hour by outcome, from scheduler logs",
+			[]string{"app", "savedsearch", "status"}, nil,
+		),
+		runTimeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "scheduler", "search_run_duration_seconds_average"),
+			"Average duration of the runs of a scheduled search in the last hour, from scheduler logs",
+			[]string{"app", "savedsearch"}, nil,
+		),
+		nextRunDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "scheduler", "search_next_run_timestamp_seconds"),
+			"Next time a scheduled search is dispatched, from saved/searches API",
+			[]string{"app", "owner", "savedsearch"}, nil,
+		),
+		searchInfoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "scheduler", "search_info"),
+			"Schedule and dispatch time range of a scheduled search, from saved/searches API",
+			[]string{"app", "owner", "savedsearch", "cron_schedule", "dispatch_earliest_time", "dispatch_latest_time"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating scheduler manager")
+	return &sm
+}
+
+func (sm *SchedulerManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(sm.logger).Log("msg", "Collecting Scheduler measures")
+	ret := true
+
+	// only the fields needed, saved searches have hundreds of them
+	// scheduled times are epoch seconds, unlike next_scheduled_time which is in the time zone of Splunk
+	params := url.Values{
+		"f": []string{
+			"is_scheduled", "disabled", "cron_schedule", "scheduled_times", "dispatch.earliest_time", "dispatch.latest_time",
+		},
+		"earliest_time": []string{"now"},
+		"latest_time":   []string{nextRunWindow},
+	}
+	searches := make([]splunklib.SavedSearch, 0)
+	if err := sm.splunk.ListAll(&searches, params); err != nil {
+		level.Error(sm.logger).Log("msg", "failed to list saved searches", "err", err)
+		ret = false
+	} else {
+		sm.collectSavedSearches(ch, searches)
+	}
+
+	activities, total, err := sm.splunk.GetSchedulerActivity(sm.max)
+	if err != nil {
+		level.Error(sm.logger).Log("msg", "failed to get scheduler activity", "err", err)
+		ret = false
+	} else {
+		sm.collectActivity(ch, activities, total)
+	}
+
+	level.Info(sm.logger).Log("msg", "Done collecting Scheduler measures", "success", ret)
+	return ret
+}
+
+// collectSavedSearches counts enabled scheduled searches of each app, and sends the dispatch of each of them
+// only the configured number of searches is detailed, sorted by app, name and owner.
+// Private searches of several owners may share a name, a search listed twice is only sent once.
+func (sm *SchedulerManager) collectSavedSearches(ch chan<- prometheus.Metric, searches []splunklib.SavedSearch) {
+	scheduled := make(map[string]float64)
+	enabled := make([]splunklib.SavedSearch, 0, len(searches))
+	for _, s := range searches {
+		if s.Content.IsScheduled && !s.Content.Disabled {
+			scheduled[s.ACL.App]++
+			enabled = append(enabled, s)
+		}
+	}
+
+	for app, count := range scheduled {
+		ch <- prometheus.MustNewConstMetric(
+			sm.scheduledDescriptor, prometheus.GaugeValue, count, app,
+		)
+	}
+
+	if len(enabled) > sm.max {
+		level.Warn(sm.logger).Log("msg", "too many scheduled searches, only the first ones are detailed", "searches", len(enabled), "max", sm.max)
+	}
+	slices.SortFunc(enabled, func(a, b splunklib.SavedSearch) int {
+		if c := cmp.Compare(a.ACL.App, b.ACL.App); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.ID.Title, b.ID.Title); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ACL.Owner, b.ACL.Owner)
+	})
+	sent := make(map[[3]string]struct{})
+	for _, s := range enabled[:min(len(enabled), sm.max)] {
+		c := s.Content
+		name := splunklib.EntryName(s.ID)
+		key := [3]string{s.ACL.App, s.ACL.Owner, name}
+		if _, ok := sent[key]; ok {
+			level.Debug(sm.logger).Log("msg", "Saved search listed twice", "savedsearch", name, "app", s.ACL.App, "owner", s.ACL.Owner)
+			continue
+		}
+		sent[key] = struct{}{}
+		ch <- prometheus.MustNewConstMetric(
+			sm.searchInfoDescriptor, prometheus.GaugeValue, 1, s.ACL.App, s.ACL.Owner, name, c.CronSchedule, c.DispatchEarliestTime, c.DispatchLatestTime,
+		)
+		if len(c.ScheduledTimes) == 0 {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			sm.nextRunDescriptor, prometheus.GaugeValue, float64(slices.Min(c.ScheduledTimes)), s.ACL.App, s.ACL.Owner, name,
+		)
+	}
+}
+
+// collectActivity sends runs by outcome and average duration of each scheduled search
+// searches over the configured limit have already been dropped by the search, only their total count is known.
+func (sm *SchedulerManager) collectActivity(ch chan<- prometheus.Metric, activities []splunklib.SchedulerActivity, total int) {
+	if total > sm.max {
+		level.Warn(sm.logger).Log("msg", "too many scheduled searches, only the most skipped are exported", "searches", total, "max", sm.max)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		sm.searchesDescriptor, prometheus.GaugeValue, float64(total),
+	)
+
+	for _, a := range activities {
+		ch <- prometheus.MustNewConstMetric(
+			sm.runsDescriptor, prometheus.GaugeValue, a.Skipped, a.App, a.SavedSearch, "skipped",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.runsDescriptor, prometheus.GaugeValue, a.Deferred, a.App, a.SavedSearch, "deferred",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.runsDescriptor, prometheus.GaugeValue, a.Success, a.App, a.SavedSearch, "success",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.runTimeDescriptor, prometheus.GaugeValue, a.RunTime, a.App, a.SavedSearch,
+		)
+	}
+}
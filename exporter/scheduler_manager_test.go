@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerSavedSearches(t *testing.T) {
+	sm := newSchedulerManager(namespace, nil, log.NewNopLogger(), config.Scheduler{})
+	searches := readTestEntries[splunklib.SavedSearch](t, "testdata/savedsearches.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		sm.collectSavedSearches(ch, searches)
+	})
+
+	expected := `
+# HELP splunk_exporter_scheduler_scheduled_searches Number of enabled scheduled searches in an app, from saved/searches API
+# TYPE splunk_exporter_scheduler_scheduled_searches gauge
+splunk_exporter_scheduler_scheduled_searches{app="search"} 3
+splunk_exporter_scheduler_scheduled_searches{app="splunk_monitoring_console"} 1
+# HELP splunk_exporter_scheduler_search_info Schedule and dispatch time range of a scheduled search, from saved/searches API
+# TYPE splunk_exporter_scheduler_search_info gauge
+splunk_exporter_scheduler_search_info{app="search",cron_schedule="0 * * * *",dispatch_earliest_time="-1h@h",dispatch_latest_time="@h",owner="system",savedsearch="Hourly errors"} 1
+splunk_exporter_scheduler_search_info{app="search",cron_schedule="0 6 * * *",dispatch_earliest_time="-1d@d",dispatch_latest_time="@d",owner="system",savedsearch="Daily report"} 1
+splunk_exporter_scheduler_search_info{app="search",cron_schedule="30 * * * *",dispatch_earliest_time="-30m",dispatch_latest_time="now",owner="jdoe",savedsearch="Hourly errors"} 1
+splunk_exporter_scheduler_search_info{app="splunk_monitoring_console",cron_schedule="3,33 * * * *",dispatch_earliest_time="-1h@h",dispatch_latest_time="@h",owner="system",savedsearch="License Usage Data Cube"} 1
+# HELP splunk_exporter_scheduler_search_next_run_timestamp_seconds Next time a scheduled search is dispatched, from saved/searches API
+# TYPE splunk_exporter_scheduler_search_next_run_timestamp_seconds gauge
+splunk_exporter_scheduler_search_next_run_timestamp_seconds{app="search",owner="jdoe",savedsearch="Hourly errors"} 1.7146422e+09
+splunk_exporter_scheduler_search_next_run_timestamp_seconds{app="search",owner="system",savedsearch="Daily report"} 1.714716e+09
+splunk_exporter_scheduler_search_next_run_timestamp_seconds{app="search",owner="system",savedsearch="Hourly errors"} 1.714644e+09
+splunk_exporter_scheduler_search_next_run_timestamp_seconds{app="splunk_monitoring_console",owner="system",savedsearch="License Usage Data Cube"} 1.71464418e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+// only the configured number of scheduled searches is detailed, all are counted
+func TestSchedulerSavedSearches_Max(t *testing.T) {
+	sm := newSchedulerManager(namespace, nil, log.NewNopLogger(), config.Scheduler{MaxSearches: 1})
+	searches := readTestEntries[splunklib.SavedSearch](t, "testdata/savedsearches.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		sm.collectSavedSearches(ch, searches)
+	})
+
+	expected := `
+# HELP splunk_exporter_scheduler_search_next_run_timestamp_seconds Next time a scheduled search is dispatched, from saved/searches API
+# TYPE splunk_exporter_scheduler_search_next_run_timestamp_seconds gauge
+splunk_exporter_scheduler_search_next_run_timestamp_seconds{app="search",owner="system",savedsearch="Daily report"} 1.714716e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "splunk_exporter_scheduler_search_next_run_timestamp_seconds"))
+	assert.Equal(t, 2+2*1, testutil.CollectAndCount(c))
+}
+
+// a search listed twice is only sent once
+func TestSchedulerSavedSearches_Duplicate(t *testing.T) {
+	sm := newSchedulerManager(namespace, nil, log.NewNopLogger(), config.Scheduler{})
+	searches := readTestEntries[splunklib.SavedSearch](t, "testdata/savedsearches.json")
+	searches = append(searches, searches...)
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		sm.collectSavedSearches(ch, searches)
+	})
+
+	assert.Equal(t, 4, testutil.CollectAndCount(c, "splunk_exporter_scheduler_search_info"))
+	assert.Equal(t, 4, testutil.CollectAndCount(c, "splunk_exporter_scheduler_search_next_run_timestamp_seconds"))
+}
+
+func TestSchedulerActivity(t *testing.T) {
+	sm := newSchedulerManager(namespace, nil, log.NewNopLogger(), config.Scheduler{MaxSearches: 1})
+	activities := []splunklib.SchedulerActivity{
+		{App: "search", SavedSearch: "Hourly errors", Skipped: 3, Deferred: 1, Success: 8, RunTime: 12.5},
+	}
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		sm.collectActivity(ch, activities, 2)
+	})
+
+	expected := `
+# HELP splunk_exporter_scheduler_search_run_duration_seconds_average Average duration of the runs of a scheduled search in the last hour, from scheduler logs
+# TYPE splunk_exporter_scheduler_search_run_duration_seconds_average gauge
+splunk_exporter_scheduler_search_run_duration_seconds_average{app="search",savedsearch="Hourly errors"} 12.5
+# HELP splunk_exporter_scheduler_search_runs Number of runs of a scheduled search in the last hour by outcome, from scheduler logs
+# TYPE splunk_exporter_scheduler_search_runs gauge
+splunk_exporter_scheduler_search_runs{app="search",savedsearch="Hourly errors",status="deferred"} 1
+splunk_exporter_scheduler_search_runs{app="search",savedsearch="Hourly errors",status="skipped"} 3
+splunk_exporter_scheduler_search_runs{app="search",savedsearch="Hourly errors",status="success"} 8
+# HELP splunk_exporter_scheduler_searches_run Number of saved searches run by the scheduler in the last hour, including those over the exported limit, from scheduler logs
+# TYPE splunk_exporter_scheduler_searches_run gauge
+splunk_exporter_scheduler_searches_run 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
@@ -0,0 +1,156 @@
+package exporter
+
+import (
+	"slices"
+	"strings"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ServerManager collects identity of the Splunk instance, and resource usage of its host
+type ServerManager struct {
+	splunk               *splunklib.Splunk // Splunk client
+	logger               log.Logger
+	infoDescriptor       *prometheus.Desc
+	startupDescriptor    *prometheus.Desc
+	cpusDescriptor       *prometheus.Desc
+	cpuDescriptor        *prometheus.Desc
+	memoryDescriptor     *prometheus.Desc
+	memoryUsedDescriptor *prometheus.Desc
+	swapDescriptor       *prometheus.Desc
+	swapUsedDescriptor   *prometheus.Desc
+	loadDescriptor       *prometheus.Desc
+}
+
+func newServerManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *ServerManager {
+
+	level.Debug(logger).Log("msg", "Initiating server manager")
+
+	sm := ServerManager{
+		splunk: spk,
+		logger: logger,
+		infoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "server", "info"),
+			"Identity of the Splunk instance, roles are sorted and comma separated, from server/info API",
+			[]string{"version", "build", "roles", "guid", "server_name", "license_state", "os"}, nil,
+		),
+		startupDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "server", "startup_timestamp_seconds"),
+			"Time splunkd started, from server/info API",
+			nil, nil,
+		),
+		cpusDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "cpus"),
+			"Number of CPUs of the host, from server/status/resource-usage/hostwide API",
+			nil, nil,
+		),
+		cpuDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "cpu_ratio"),
+			"Share of CPU time of the host by mode, between 0 and 1, from server/status/resource-usage/hostwide API",
+			[]string{"mode"}, nil,
+		),
+		memoryDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "memory_bytes"),
+			"Physical memory of the host, from server/status/resource-usage/hostwide API",
+			nil, nil,
+		),
+		memoryUsedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "memory_used_bytes"),
+			"Physical memory used on the host, from server/status/resource-usage/hostwide API",
+			nil, nil,
+		),
+		swapDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "swap_bytes"),
+			"Swap space of the host, from server/status/resource-usage/hostwide API",
+			nil, nil,
+		),
+		swapUsedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "swap_used_bytes"),
+			"Swap space used on the host, from server/status/resource-usage/hostwide API",
+			nil, nil,
+		),
+		loadDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "load1_normalized"),
+			"Load average over 1 minute divided by the number of CPUs, from server/status/resource-usage/hostwide API",
+			nil, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating server manager")
+	return &sm
+}
+
+func (sm *ServerManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(sm.logger).Log("msg", "Collecting Server measures")
+	ret := true
+
+	info := splunklib.ServerInfo{}
+	if err := sm.splunk.Client.Read(&info); err != nil {
+		level.Error(sm.logger).Log("msg", "failed to read server info", "err", err)
+		ret = false
+	} else {
+		sm.collectInfo(ch, &info)
+	}
+
+	usage := splunklib.ServerResourceUsageHost{}
+	if err := sm.splunk.Client.Read(&usage); err != nil {
+		level.Error(sm.logger).Log("msg", "failed to read host resource usage", "err", err)
+		ret = false
+	} else {
+		sm.collectResourceUsage(ch, &usage)
+	}
+
+	level.Info(sm.logger).Log("msg", "Done collecting Server measures", "success", ret)
+	return ret
+}
+
+// collectInfo sends identity of the instance as labels of an info metric, and its startup time
+func (sm *ServerManager) collectInfo(ch chan<- prometheus.Metric, info *splunklib.ServerInfo) {
+	c := info.Content
+	roles := slices.Clone(c.ServerRoles)
+	slices.Sort(roles)
+
+	ch <- prometheus.MustNewConstMetric(
+		sm.infoDescriptor, prometheus.GaugeValue, 1,
+		c.Version, c.Build, strings.Join(roles, ","), c.GUID, c.ServerName, c.LicenseState, c.OSName,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.startupDescriptor, prometheus.GaugeValue, float64(c.StartupTime),
+	)
+}
+
+// collectResourceUsage sends CPU, memory and load of the host
+func (sm *ServerManager) collectResourceUsage(ch chan<- prometheus.Metric, usage *splunklib.ServerResourceUsageHost) {
+	c := usage.Content
+
+	ch <- prometheus.MustNewConstMetric(
+		sm.cpusDescriptor, prometheus.GaugeValue, float64(c.CPUCount),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.cpuDescriptor, prometheus.GaugeValue, float64(c.CPUUserPct)/100, "user",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.cpuDescriptor, prometheus.GaugeValue, float64(c.CPUSystemPct)/100, "system",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.cpuDescriptor, prometheus.GaugeValue, float64(c.CPUIdlePct)/100, "idle",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.memoryDescriptor, prometheus.GaugeValue, float64(c.Mem)*bytesPerMB,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.memoryUsedDescriptor, prometheus.GaugeValue, float64(c.MemUsed)*bytesPerMB,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.swapDescriptor, prometheus.GaugeValue, float64(c.Swap)*bytesPerMB,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.swapUsedDescriptor, prometheus.GaugeValue, float64(c.SwapUsed)*bytesPerMB,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.loadDescriptor, prometheus.GaugeValue, float64(c.NormalizedLoadAvg1Min),
+	)
+}
@@ -0,0 +1,52 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info":                           "testdata/serverinfo.json",
+		"/services/server/status/resource-usage/hostwide": "testdata/serverresourceusagehostwide.json",
+	})
+	sm := newServerManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = sm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_host_cpu_ratio Share of CPU time of the host by mode, between 0 and 1, from server/status/resource-usage/hostwide API
+# TYPE splunk_exporter_host_cpu_ratio gauge
+splunk_exporter_host_cpu_ratio{mode="idle"} 0.7125
+splunk_exporter_host_cpu_ratio{mode="system"} 0.065
+splunk_exporter_host_cpu_ratio{mode="user"} 0.2225
+# HELP splunk_exporter_host_load1_normalized Load average over 1 minute divided by the number of CPUs, from server/status/resource-usage/hostwide API
+# TYPE splunk_exporter_host_load1_normalized gauge
+splunk_exporter_host_load1_normalized 0.45
+# HELP splunk_exporter_host_memory_used_bytes Physical memory used on the host, from server/status/resource-usage/hostwide API
+# TYPE splunk_exporter_host_memory_used_bytes gauge
+splunk_exporter_host_memory_used_bytes 1.6694378496e+10
+# HELP splunk_exporter_server_info Identity of the Splunk instance, roles are sorted and comma separated, from server/info API
+# TYPE splunk_exporter_server_info gauge
+splunk_exporter_server_info{build="78803f08aabb",guid="8F8096AF-A456-4974-92FB-966103FA9752",license_state="OK",os="Linux",roles="cluster_manager,kv_store,license_manager,search_head",server_name="cm1",version="9.2.1"} 1
+# HELP splunk_exporter_server_startup_timestamp_seconds Time splunkd started, from server/info API
+# TYPE splunk_exporter_server_startup_timestamp_seconds gauge
+splunk_exporter_server_startup_timestamp_seconds 1.7146e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_host_cpu_ratio",
+		"splunk_exporter_host_load1_normalized",
+		"splunk_exporter_host_memory_used_bytes",
+		"splunk_exporter_server_info",
+		"splunk_exporter_server_startup_timestamp_seconds",
+	))
+	assert.True(t, ok)
+}
@@ -0,0 +1,268 @@
+package exporter
+
+import (
+	"slices"
+	"sync"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// shcMemberStatuses are the known statuses of a search head cluster member
+var shcMemberStatuses = []string{"Up", "Down", "Restarting", "ShuttingDown", "ManualDetention", "Detention"}
+
+// SHClusterManager collects search head clustering measures from a cluster member
+type SHClusterManager struct {
+	splunk                        *splunklib.Splunk // Splunk client
+	logger                        log.Logger
+	captainDescriptor             *prometheus.Desc
+	captainElectedDescriptor      *prometheus.Desc
+	captainChangesDescriptor      *prometheus.Desc
+	rollingRestartDescriptor      *prometheus.Desc
+	maintenanceModeDescriptor     *prometheus.Desc
+	serviceReadyDescriptor        *prometheus.Desc
+	memberUpDescriptor            *prometheus.Desc
+	memberStatusDescriptor        *prometheus.Desc
+	memberArtifactsDescriptor     *prometheus.Desc
+	memberReplicationsDescriptor  *prometheus.Desc
+	memberPendingJobsDescriptor   *prometheus.Desc
+	memberHeartbeatDescriptor     *prometheus.Desc
+	localRegisteredDescriptor     *prometheus.Desc
+	localReplicationDescriptor    *prometheus.Desc
+	localRestartPendingDescriptor *prometheus.Desc
+	localSearchesDescriptor       *prometheus.Desc
+
+	// captain changes are derived from successive scrapes
+	captainMu      sync.Mutex // guards lastCaptain and captainChanges
+	lastCaptain    string     // ID of the captain seen during the last scrape
+	captainChanges float64
+}
+
+func newSHClusterManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *SHClusterManager {
+
+	level.Debug(logger).Log("msg", "Initiating search head cluster manager")
+
+	sm := SHClusterManager{
+		splunk: spk,
+		logger: logger,
+		captainDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "captain_info"),
+			"Identity of the search head cluster captain, from shcluster/captain/info API",
+			[]string{"captain", "mgmt_uri"}, nil,
+		),
+		captainElectedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "captain_elected_timestamp_seconds"),
+			"When the current search head cluster captain was elected, from shcluster/captain/info API",
+			nil, nil,
+		),
+		captainChangesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "captain_changes_total"),
+			"Number of captain changes seen by the exporter since it started",
+			nil, nil,
+		),
+		rollingRestartDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "rolling_restart"),
+			"Whether the search head cluster is in a rolling restart, from shcluster/captain/info API",
+			nil, nil,
+		),
+		maintenanceModeDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "maintenance_mode"),
+			"Whether the search head cluster is in maintenance mode, from shcluster/captain/info API",
+			nil, nil,
+		),
+		serviceReadyDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "service_ready"),
+			"Whether the search head cluster captain is ready to provide service, from shcluster/captain/info API",
+			nil, nil,
+		),
+		memberUpDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "member_up"),
+			"Whether a search head cluster member has the Up status, from shcluster/captain/members API",
+			[]string{"member", "site"}, nil,
+		),
+		memberStatusDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "member_status"),
+			"Status of a search head cluster member, 1 for its current status, from shcluster/captain/members API",
+			[]string{"member", "status"}, nil,
+		),
+		memberArtifactsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "member_artifacts"),
+			"Number of search artifacts on a search head cluster member, from shcluster/captain/members API",
+			[]string{"member"}, nil,
+		),
+		memberReplicationsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "member_artifact_replications"),
+			"Number of artifact replications in progress on a search head cluster member, from shcluster/captain/members API",
+			[]string{"member"}, nil,
+		),
+		memberPendingJobsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "member_pending_jobs"),
+			"Number of jobs the captain is waiting for on a search head cluster member, from shcluster/captain/members API",
+			[]string{"member"}, nil,
+		),
+		memberHeartbeatDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "member_last_heartbeat_timestamp_seconds"),
+			"Last heartbeat the captain received from a search head cluster member, from shcluster/captain/members API",
+			[]string{"member"}, nil,
+		),
+		localRegisteredDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "local_registered"),
+			"Whether the scraped member is registered with the captain, from shcluster/member/info API",
+			nil, nil,
+		),
+		localReplicationDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "local_artifact_replication_enabled"),
+			"Whether artifact replication is enabled on the scraped member, from shcluster/member/info API",
+			nil, nil,
+		),
+		localRestartPendingDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "local_restart_pending"),
+			"Whether the scraped member is waiting for a restart, from shcluster/member/info API",
+			[]string{"restart_state"}, nil,
+		),
+		localSearchesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shc", "local_active_searches"),
+			"Number of searches running on the scraped member, from shcluster/member/info API",
+			[]string{"type"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating search head cluster manager")
+	return &sm
+}
+
+// CollectMeasures collects search head clustering measures, it does nothing when the instance is not a cluster member
+func (sm *SHClusterManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	isMember, err := hasServerRole(sm.splunk, shcMemberRoles...)
+	if err != nil {
+		level.Error(sm.logger).Log("msg", "failed to read server roles", "err", err)
+		return false
+	}
+	if !isMember {
+		level.Debug(sm.logger).Log("msg", "Instance is not a search head cluster member, skipping SHC measures")
+		return true
+	}
+
+	level.Info(sm.logger).Log("msg", "Collecting SHC measures")
+	ret := true
+
+	captain := splunklib.SHClusterCaptainInfo{}
+	if err := sm.splunk.Client.Read(&captain); err != nil {
+		level.Error(sm.logger).Log("msg", "failed to read search head cluster captain info", "err", err)
+		ret = false
+	} else {
+		sm.collectCaptain(ch, &captain)
+	}
+
+	members := make([]splunklib.SHClusterCaptainMember, 0)
+	if err := sm.splunk.ListAll(&members, nil); err != nil {
+		level.Error(sm.logger).Log("msg", "failed to list search head cluster members", "err", err)
+		ret = false
+	} else {
+		sm.collectMembers(ch, members)
+	}
+
+	local := splunklib.SHClusterMemberInfo{}
+	if err := sm.splunk.Client.Read(&local); err != nil {
+		level.Error(sm.logger).Log("msg", "failed to read search head cluster member info", "err", err)
+		ret = false
+	} else {
+		sm.collectLocalMember(ch, &local)
+	}
+
+	level.Info(sm.logger).Log("msg", "Done collecting SHC measures", "success", ret)
+	return ret
+}
+
+// collectCaptain sends captain identity and cluster wide flags, and counts captain changes
+func (sm *SHClusterManager) collectCaptain(ch chan<- prometheus.Metric, captain *splunklib.SHClusterCaptainInfo) {
+	ch <- prometheus.MustNewConstMetric(
+		sm.captainDescriptor, prometheus.GaugeValue, 1.0, captain.Content.Label, captain.Content.MgmtURI,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.captainElectedDescriptor, prometheus.GaugeValue, float64(captain.Content.ElectedCaptain),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.rollingRestartDescriptor, prometheus.GaugeValue, boolToFloat(bool(captain.Content.RollingRestartFlag)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.maintenanceModeDescriptor, prometheus.GaugeValue, boolToFloat(bool(captain.Content.MaintenanceMode)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.serviceReadyDescriptor, prometheus.GaugeValue, boolToFloat(bool(captain.Content.ServiceReadyFlag)),
+	)
+
+	id := captain.Content.ID
+	if id == "" {
+		id = captain.Content.Label
+	}
+	sm.captainMu.Lock()
+	if sm.lastCaptain != "" && id != sm.lastCaptain {
+		level.Info(sm.logger).Log("msg", "Search head cluster captain changed", "previous", sm.lastCaptain, "current", id)
+		sm.captainChanges++
+	}
+	sm.lastCaptain = id
+	changes := sm.captainChanges
+	sm.captainMu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(
+		sm.captainChangesDescriptor, prometheus.CounterValue, changes,
+	)
+}
+
+// collectMembers sends status and artifacts of each member, as seen by the captain
+func (sm *SHClusterManager) collectMembers(ch chan<- prometheus.Metric, members []splunklib.SHClusterCaptainMember) {
+	for _, m := range members {
+		name := m.Content.Label
+		if name == "" {
+			name = m.ID.Title
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			sm.memberUpDescriptor, prometheus.GaugeValue, boolToFloat(m.Content.Status == "Up"), name, m.Content.Site,
+		)
+		statuses := shcMemberStatuses
+		if !slices.Contains(statuses, m.Content.Status) {
+			statuses = append(slices.Clone(statuses), m.Content.Status)
+		}
+		for _, s := range statuses {
+			ch <- prometheus.MustNewConstMetric(
+				sm.memberStatusDescriptor, prometheus.GaugeValue, boolToFloat(m.Content.Status == s), name, s,
+			)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			sm.memberArtifactsDescriptor, prometheus.GaugeValue, float64(m.Content.ArtifactCount), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.memberReplicationsDescriptor, prometheus.GaugeValue, float64(m.Content.ReplicationCount), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.memberPendingJobsDescriptor, prometheus.GaugeValue, float64(m.Content.PendingJobCount), name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.memberHeartbeatDescriptor, prometheus.GaugeValue, float64(m.Content.LastHeartbeat), name,
+		)
+	}
+}
+
+// collectLocalMember sends the state of the scraped member
+func (sm *SHClusterManager) collectLocalMember(ch chan<- prometheus.Metric, local *splunklib.SHClusterMemberInfo) {
+	ch <- prometheus.MustNewConstMetric(
+		sm.localRegisteredDescriptor, prometheus.GaugeValue, boolToFloat(bool(local.Content.IsRegistered)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.localReplicationDescriptor, prometheus.GaugeValue, boolToFloat(!bool(local.Content.NoArtifactReplications)),
+	)
+	restartPending := local.Content.RestartState != "" && local.Content.RestartState != "NoRestart"
+	ch <- prometheus.MustNewConstMetric(
+		sm.localRestartPendingDescriptor, prometheus.GaugeValue, boolToFloat(restartPending), local.Content.RestartState,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.localSearchesDescriptor, prometheus.GaugeValue, float64(local.Content.ActiveHistoricalSearchCount), "historical",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		sm.localSearchesDescriptor, prometheus.GaugeValue, float64(local.Content.ActiveRealtimeSearchCount), "realtime",
+	)
+}
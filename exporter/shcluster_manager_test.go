@@ -0,0 +1,98 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSHClusterManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info":               "testdata/serverinfo-shc.json",
+		"/services/shcluster/captain/info":    "testdata/shclustercaptaininfo.json",
+		"/services/shcluster/captain/members": "testdata/shclustercaptainmembers.json",
+		"/services/shcluster/member/info":     "testdata/shclustermemberinfo.json",
+	})
+	sm := newSHClusterManager(namespace, spk, log.NewNopLogger())
+
+	var ret bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ret = sm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_shc_captain_changes_total Number of captain changes seen by the exporter since it started
+# TYPE splunk_exporter_shc_captain_changes_total counter
+splunk_exporter_shc_captain_changes_total 0
+# HELP splunk_exporter_shc_captain_elected_timestamp_seconds When the current search head cluster captain was elected, from shcluster/captain/info API
+# TYPE splunk_exporter_shc_captain_elected_timestamp_seconds gauge
+splunk_exporter_shc_captain_elected_timestamp_seconds 1.71461e+09
+# HELP splunk_exporter_shc_captain_info Identity of the search head cluster captain, from shcluster/captain/info API
+# TYPE splunk_exporter_shc_captain_info gauge
+splunk_exporter_shc_captain_info{captain="sh1",mgmt_uri="https://sh1.splunk.local:8089"} 1
+# HELP splunk_exporter_shc_local_active_searches Number of searches running on the scraped member, from shcluster/member/info API
+# TYPE splunk_exporter_shc_local_active_searches gauge
+splunk_exporter_shc_local_active_searches{type="historical"} 5
+splunk_exporter_shc_local_active_searches{type="realtime"} 1
+# HELP splunk_exporter_shc_local_restart_pending Whether the scraped member is waiting for a restart, from shcluster/member/info API
+# TYPE splunk_exporter_shc_local_restart_pending gauge
+splunk_exporter_shc_local_restart_pending{restart_state="NoRestart"} 0
+# HELP splunk_exporter_shc_member_artifact_replications Number of artifact replications in progress on a search head cluster member, from shcluster/captain/members API
+# TYPE splunk_exporter_shc_member_artifact_replications gauge
+splunk_exporter_shc_member_artifact_replications{member="sh1"} 0
+splunk_exporter_shc_member_artifact_replications{member="sh2"} 2
+splunk_exporter_shc_member_artifact_replications{member="sh3"} 0
+# HELP splunk_exporter_shc_member_up Whether a search head cluster member has the Up status, from shcluster/captain/members API
+# TYPE splunk_exporter_shc_member_up gauge
+splunk_exporter_shc_member_up{member="sh1",site="default"} 1
+splunk_exporter_shc_member_up{member="sh2",site="default"} 1
+splunk_exporter_shc_member_up{member="sh3",site="default"} 0
+# HELP splunk_exporter_shc_rolling_restart Whether the search head cluster is in a rolling restart, from shcluster/captain/info API
+# TYPE splunk_exporter_shc_rolling_restart gauge
+splunk_exporter_shc_rolling_restart 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_shc_captain_changes_total",
+		"splunk_exporter_shc_captain_elected_timestamp_seconds",
+		"splunk_exporter_shc_captain_info",
+		"splunk_exporter_shc_local_active_searches",
+		"splunk_exporter_shc_local_restart_pending",
+		"splunk_exporter_shc_member_artifact_replications",
+		"splunk_exporter_shc_member_up",
+		"splunk_exporter_shc_rolling_restart",
+	))
+	assert.True(t, ret)
+	assert.Equal(t, 3*len(shcMemberStatuses), testutil.CollectAndCount(c, "splunk_exporter_shc_member_status"))
+}
+
+func TestSHClusterManager_CaptainChanges(t *testing.T) {
+	sm := newSHClusterManager(namespace, nil, log.NewNopLogger())
+	captain := func(id string) *splunklib.SHClusterCaptainInfo {
+		return &splunklib.SHClusterCaptainInfo{Content: splunklib.SHClusterCaptainInfoContent{ID: id, Label: id}}
+	}
+
+	for _, id := range []string{"sh1", "sh1", "sh2", "sh2", "sh1"} {
+		c := testCollector(func(ch chan<- prometheus.Metric) {
+			sm.collectCaptain(ch, captain(id))
+		})
+		testutil.CollectAndCount(c)
+	}
+
+	assert.Equal(t, 2.0, sm.captainChanges)
+}
+
+func TestSHClusterManager_NotAMember(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/server/info": "testdata/serverinfo-indexer.json",
+	})
+	sm := newSHClusterManager(namespace, spk, log.NewNopLogger())
+
+	ch := make(chan prometheus.Metric, 100)
+	assert.True(t, sm.CollectMeasures(ch))
+	assert.Empty(t, ch)
+}
@@ -0,0 +1,154 @@
+package exporter
+
+import (
+	"net/url"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SmartStoreManager collects SmartStore cache manager activity
+// REST API only tells the buckets in cache, activity comes from cache manager metrics in _internal.
+type SmartStoreManager struct {
+	splunk                  *splunklib.Splunk // Splunk client
+	logger                  log.Logger
+	cachedBucketsDescriptor *prometheus.Desc
+	hitsDescriptor          *prometheus.Desc
+	missesDescriptor        *prometheus.Desc
+	evictionsDescriptor     *prometheus.Desc
+	downloadBytesDescriptor *prometheus.Desc
+	uploadBytesDescriptor   *prometheus.Desc
+	downloadQueueDescriptor *prometheus.Desc
+	uploadQueueDescriptor   *prometheus.Desc
+}
+
+func newSmartStoreManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *SmartStoreManager {
+
+	level.Debug(logger).Log("msg", "Initiating SmartStore manager")
+
+	sm := SmartStoreManager{
+		splunk: spk,
+		logger: logger,
+		cachedBucketsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "cached_buckets"),
+			"Number of buckets known by the cache manager of the scraped instance, from admin/cacheman API",
+			nil, nil,
+		),
+		hitsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "cache_hits"),
+			"Bucket lookups served from the cache in the last 5 minutes, from cache manager metrics",
+			[]string{"host"}, nil,
+		),
+		missesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "cache_misses"),
+			"Bucket lookups needing a download from the remote store in the last 5 minutes, from cache manager metrics",
+			[]string{"host"}, nil,
+		),
+		evictionsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "cache_evictions"),
+			"Buckets evicted from the cache in the last 5 minutes, from cache manager metrics",
+			[]string{"host"}, nil,
+		),
+		downloadBytesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "download_bytes"),
+			"Bytes downloaded from the remote store in the last 5 minutes, from cache manager metrics",
+			[]string{"host"}, nil,
+		),
+		uploadBytesDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "upload_bytes"),
+			"Bytes uploaded to the remote store in the last 5 minutes, from cache manager metrics",
+			[]string{"host"}, nil,
+		),
+		downloadQueueDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "download_queue_length"),
+			"Latest number of downloads waiting in the queue, from cache manager metrics",
+			[]string{"host"}, nil,
+		),
+		uploadQueueDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "smartstore", "upload_queue_length"),
+			"Latest number of uploads waiting in the queue, from cache manager metrics",
+			[]string{"host"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating SmartStore manager")
+	return &sm
+}
+
+func (sm *SmartStoreManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	enabled, err := sm.isEnabled()
+	if err != nil {
+		level.Error(sm.logger).Log("msg", "failed to list indexes", "err", err)
+		return false
+	}
+	if !enabled {
+		level.Debug(sm.logger).Log("msg", "No SmartStore index, skipping SmartStore measures")
+		return true
+	}
+
+	level.Info(sm.logger).Log("msg", "Collecting SmartStore measures")
+	ret := true
+
+	// the cache manager API only exists where buckets are cached, like on indexers
+	if cached, err := sm.splunk.CountEntries(splunklib.CacheManagerBucket{}, nil); err != nil {
+		level.Debug(sm.logger).Log("msg", "cache manager API is not available, skipping cached buckets", "err", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(
+			sm.cachedBucketsDescriptor, prometheus.GaugeValue, float64(cached),
+		)
+	}
+
+	activities, err := sm.splunk.GetCacheManagerActivity()
+	if err != nil {
+		level.Error(sm.logger).Log("msg", "failed to get cache manager activity", "err", err)
+		ret = false
+	} else {
+		sm.collectActivity(ch, activities)
+	}
+
+	level.Info(sm.logger).Log("msg", "Done collecting SmartStore measures", "success", ret)
+	return ret
+}
+
+// isEnabled tells whether an index has a remote storage
+func (sm *SmartStoreManager) isEnabled() (bool, error) {
+	indexes := make([]splunklib.DataIndex, 0)
+	if err := sm.splunk.ListAll(&indexes, url.Values{"f": []string{"remotePath"}}); err != nil {
+		return false, err
+	}
+	for _, i := range indexes {
+		if i.Content.RemotePath != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// collectActivity sends what the cache manager of each host did recently
+func (sm *SmartStoreManager) collectActivity(ch chan<- prometheus.Metric, activities []splunklib.CacheManagerActivity) {
+	for _, a := range activities {
+		ch <- prometheus.MustNewConstMetric(
+			sm.hitsDescriptor, prometheus.GaugeValue, a.Hits, a.Host,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.missesDescriptor, prometheus.GaugeValue, a.Misses, a.Host,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.evictionsDescriptor, prometheus.GaugeValue, a.Evictions, a.Host,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.downloadBytesDescriptor, prometheus.GaugeValue, a.DownloadBytes, a.Host,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.uploadBytesDescriptor, prometheus.GaugeValue, a.UploadBytes, a.Host,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.downloadQueueDescriptor, prometheus.GaugeValue, a.DownloadQueue, a.Host,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			sm.uploadQueueDescriptor, prometheus.GaugeValue, a.UploadQueue, a.Host,
+		)
+	}
+}
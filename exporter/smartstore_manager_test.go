@@ -0,0 +1,79 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmartStoreManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/data/indexes":   "testdata/dataindexes.json",
+		"/services/admin/cacheman": "testdata/admincacheman.json",
+		"/services/search/v2/jobs": "testdata/searchcachemgractivity.json",
+	})
+	sm := newSmartStoreManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = sm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_smartstore_cache_hits Bucket lookups served from the cache in the last 5 minutes, from cache manager metrics
+# TYPE splunk_exporter_smartstore_cache_hits gauge
+splunk_exporter_smartstore_cache_hits{host="idx1"} 1520
+splunk_exporter_smartstore_cache_hits{host="idx2"} 1304
+# HELP splunk_exporter_smartstore_cache_misses Bucket lookups needing a download from the remote store in the last 5 minutes, from cache manager metrics
+# TYPE splunk_exporter_smartstore_cache_misses gauge
+splunk_exporter_smartstore_cache_misses{host="idx1"} 12
+splunk_exporter_smartstore_cache_misses{host="idx2"} 0
+# HELP splunk_exporter_smartstore_cached_buckets Number of buckets known by the cache manager of the scraped instance, from admin/cacheman API
+# TYPE splunk_exporter_smartstore_cached_buckets gauge
+splunk_exporter_smartstore_cached_buckets 3
+# HELP splunk_exporter_smartstore_download_queue_length Latest number of downloads waiting in the queue, from cache manager metrics
+# TYPE splunk_exporter_smartstore_download_queue_length gauge
+splunk_exporter_smartstore_download_queue_length{host="idx1"} 2
+splunk_exporter_smartstore_download_queue_length{host="idx2"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_smartstore_cache_hits",
+		"splunk_exporter_smartstore_cache_misses",
+		"splunk_exporter_smartstore_cached_buckets",
+		"splunk_exporter_smartstore_download_queue_length",
+	))
+	assert.True(t, ok)
+	assert.Equal(t, 1+2*7, testutil.CollectAndCount(c))
+}
+
+func TestSmartStoreManager_NoCacheManagerAPI(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/data/indexes":   "testdata/dataindexes.json",
+		"/services/search/v2/jobs": "testdata/searchcachemgractivity.json",
+	})
+	sm := newSmartStoreManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = sm.CollectMeasures(ch)
+	})
+
+	assert.Equal(t, 0, testutil.CollectAndCount(c, "splunk_exporter_smartstore_cached_buckets"))
+	assert.Equal(t, 2, testutil.CollectAndCount(c, "splunk_exporter_smartstore_cache_hits"))
+	assert.True(t, ok)
+}
+
+func TestSmartStoreManager_NotConfigured(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/data/indexes": "testdata/dataindexes-local.json",
+	})
+	sm := newSmartStoreManager(namespace, spk, log.NewNopLogger())
+
+	ch := make(chan prometheus.Metric, 100)
+	assert.True(t, sm.CollectMeasures(ch))
+	assert.Empty(t, ch)
+}
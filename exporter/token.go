@@ -0,0 +1,103 @@
+package exporter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/splunk/go-splunk-client/pkg/authenticators"
+)
+
+const (
+	// tokenExpiryWarning is how long before expiration of its token the exporter starts warning
+	tokenExpiryWarning = 7 * 24 * time.Hour
+	// tokenWarningInterval is the minimum time between two warnings, scrapes are much more frequent
+	tokenWarningInterval = time.Hour
+)
+
+var authTokenExpiry = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "auth", "token_expiry_timestamp_seconds"),
+	"Expiration time of the token used by the exporter, from its claims.",
+	nil, nil,
+)
+
+// tokenClaims are the claims of a Splunk authentication token used by the exporter
+type tokenClaims struct {
+	Expiration int64 `json:"exp"` // 0 when the token never expires
+}
+
+// parseTokenClaims decodes the claims of a JWT token, without verifying its signature
+// the exporter only reads its own token, Splunk verifies it on each request.
+func parseTokenClaims(token string) (tokenClaims, error) {
+	var claims tokenClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, fmt.Errorf("token is not a JWT, it has %d parts", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims, fmt.Errorf("could not decode token claims: %w", err)
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, fmt.Errorf("could not parse token claims: %w", err)
+	}
+	return claims, nil
+}
+
+// configuredToken returns the token the exporter authenticates with, false with password authentication
+// it follows configuration reloads, which replace the authenticator of the client.
+func configuredToken(spk *splunklib.Splunk) (string, bool) {
+	t, ok := spk.Client.Authenticator.(authenticators.Token)
+	return t.Token, ok
+}
+
+// collectTokenExpiry sends the expiration time of the token used by the exporter, and warns when it approaches
+// nothing is sent with password authentication, or when the token does not expire.
+// Claims are read locally, so the expiration is known even when Splunk rejects the token.
+func (e *Exporter) collectTokenExpiry(ch chan<- prometheus.Metric, now time.Time) {
+	token, ok := configuredToken(e.splunk)
+	if !ok {
+		return
+	}
+	claims, err := parseTokenClaims(token)
+	if err != nil {
+		level.Debug(e.logger).Log("msg", "could not read the expiration of the token", "err", err)
+		return
+	}
+	if claims.Expiration == 0 {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		authTokenExpiry, prometheus.GaugeValue, float64(claims.Expiration),
+	)
+	e.warnTokenExpiry(time.Unix(claims.Expiration, 0), now)
+}
+
+// warnTokenExpiry logs when the token expires within tokenExpiryWarning, at most once per tokenWarningInterval
+// it returns true if it logged.
+func (e *Exporter) warnTokenExpiry(expiry time.Time, now time.Time) bool {
+	left := expiry.Sub(now)
+	if left > tokenExpiryWarning {
+		return false
+	}
+
+	e.tokenWarnMu.Lock()
+	defer e.tokenWarnMu.Unlock()
+	if !e.tokenWarnedAt.IsZero() && now.Sub(e.tokenWarnedAt) < tokenWarningInterval {
+		return false
+	}
+	e.tokenWarnedAt = now
+
+	if left <= 0 {
+		level.Error(e.logger).Log("msg", "The Splunk token of the exporter expired, Splunk rejects its requests with 401 Unauthorized, a new token must be configured", "expired_at", expiry.UTC())
+	} else {
+		level.Warn(e.logger).Log("msg", "The Splunk token of the exporter expires soon, a new token must be configured", "expires_at", expiry.UTC(), "expires_in", left.Round(time.Minute))
+	}
+	return true
+}
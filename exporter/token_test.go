@@ -0,0 +1,86 @@
+package exporter
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/splunk/go-splunk-client/pkg/authenticators"
+	splunkclient "github.com/splunk/go-splunk-client/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// testToken returns an unsigned JWT token with the given claims
+func testToken(claims string) string {
+	return "eyJhbGciOiJIUzUxMiJ9." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2lnbmF0dXJl"
+}
+
+func TestParseTokenClaims(t *testing.T) {
+	claims, err := parseTokenClaims(testToken(`{"sub": "admin", "exp": 1717070456}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1717070456), claims.Expiration)
+}
+
+func TestParseTokenClaims_Invalid(t *testing.T) {
+	for name, token := range map[string]string{
+		"not a JWT":      "changeme",
+		"not base64":     "header.!!!.signature",
+		"not JSON":       testToken("claims"),
+		"not an integer": testToken(`{"exp": "never"}`),
+	} {
+		_, err := parseTokenClaims(token)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCollectTokenExpiry(t *testing.T) {
+	spk := &splunklib.Splunk{Client: &splunkclient.Client{
+		Authenticator: authenticators.Token{Token: testToken(`{"sub": "admin", "exp": 1717070456}`)},
+	}}
+	e := &Exporter{splunk: spk, logger: log.NewNopLogger()}
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		e.collectTokenExpiry(ch, time.Unix(1714478456, 0))
+	})
+
+	expected := `
+# HELP splunk_exporter_auth_token_expiry_timestamp_seconds Expiration time of the token used by the exporter, from its claims.
+# TYPE splunk_exporter_auth_token_expiry_timestamp_seconds gauge
+splunk_exporter_auth_token_expiry_timestamp_seconds 1.717070456e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestCollectTokenExpiry_NoExpiry(t *testing.T) {
+	for name, authenticator := range map[string]splunkclient.Authenticator{
+		"password":      &authenticators.Password{Username: "admin", Password: "changeme"},
+		"not a JWT":     authenticators.Token{Token: "changeme"},
+		"never expires": authenticators.Token{Token: testToken(`{"sub": "admin"}`)},
+	} {
+		spk := &splunklib.Splunk{Client: &splunkclient.Client{Authenticator: authenticator}}
+		e := &Exporter{splunk: spk, logger: log.NewNopLogger()}
+
+		c := testCollector(func(ch chan<- prometheus.Metric) {
+			e.collectTokenExpiry(ch, time.Now())
+		})
+		assert.Equal(t, 0, testutil.CollectAndCount(c), name)
+	}
+}
+
+func TestWarnTokenExpiry(t *testing.T) {
+	e := &Exporter{logger: log.NewNopLogger()}
+	expiry := time.Unix(1717070456, 0)
+
+	assert.False(t, e.warnTokenExpiry(expiry, expiry.Add(-30*24*time.Hour)), "far from expiry")
+	now := expiry.Add(-2 * 24 * time.Hour)
+	assert.True(t, e.warnTokenExpiry(expiry, now), "close to expiry")
+	assert.False(t, e.warnTokenExpiry(expiry, now.Add(time.Minute)), "warned recently")
+	assert.True(t, e.warnTokenExpiry(expiry, now.Add(tokenWarningInterval)), "warned long ago")
+	assert.True(t, e.warnTokenExpiry(expiry, expiry.Add(24*time.Hour)), "expired")
+}
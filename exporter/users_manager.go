@@ -0,0 +1,171 @@
+package exporter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UsersManager collects user sessions, locked out users and authentication tokens
+// session keys and tokens are secrets, they are never read nor exported.
+type UsersManager struct {
+	splunk                 *splunklib.Splunk // Splunk client
+	logger                 log.Logger
+	maxUsers               int // maximum number of users exported with their sessions
+	sessionsDescriptor     *prometheus.Desc
+	userSessionsDescriptor *prometheus.Desc
+	lockedOutDescriptor    *prometheus.Desc
+	tokenExpiryDescriptor  *prometheus.Desc
+}
+
+func newUsersManager(namespace string, spk *splunklib.Splunk, logger log.Logger, conf config.Users) *UsersManager {
+
+	level.Debug(logger).Log("msg", "Initiating users manager")
+
+	maxUsers := conf.MaxUsers
+	if maxUsers <= 0 {
+		maxUsers = defaultMaxUsers
+	}
+
+	um := UsersManager{
+		splunk:   spk,
+		logger:   logger,
+		maxUsers: maxUsers,
+		sessionsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sessions"),
+			"Number of active user sessions, from authentication/httpauth-tokens API",
+			nil, nil,
+		),
+		userSessionsDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "user", "sessions"),
+			"Number of active sessions of a user, users over the configured limit are summed as \"_other\", from authentication/httpauth-tokens API",
+			[]string{"user"}, nil,
+		),
+		lockedOutDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "users_locked_out"),
+			"Number of users locked out after failed logins, from authentication/users API",
+			nil, nil,
+		),
+		tokenExpiryDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "token", "expiry_timestamp_seconds"),
+			"Expiration time of an enabled authentication token, tokens without expiration are not exported, from authorization/tokens API",
+			[]string{"id", "user", "audience"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating users manager")
+	return &um
+}
+
+func (um *UsersManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	level.Info(um.logger).Log("msg", "Collecting Users measures")
+	ret := true
+
+	sessions := make([]splunklib.AuthenticationSession, 0)
+	if err := um.splunk.ListAll(&sessions, nil); err != nil {
+		level.Error(um.logger).Log("msg", "failed to list sessions", "err", err)
+		ret = false
+	} else {
+		um.collectSessions(ch, sessions)
+	}
+
+	users := make([]splunklib.AuthenticationUser, 0)
+	if err := um.splunk.ListAll(&users, nil); err != nil {
+		level.Error(um.logger).Log("msg", "failed to list users", "err", err)
+		ret = false
+	} else {
+		um.collectUsers(ch, users)
+	}
+
+	tokens := make([]splunklib.AuthorizationToken, 0)
+	if err := um.splunk.ListAll(&tokens, nil); err != nil {
+		level.Error(um.logger).Log("msg", "failed to list tokens", "err", err)
+		ret = false
+	} else {
+		um.collectTokens(ch, tokens)
+	}
+
+	level.Info(um.logger).Log("msg", "Done collecting Users measures", "success", ret)
+	return ret
+}
+
+// collectSessions sends the number of sessions, in total and by user
+func (um *UsersManager) collectSessions(ch chan<- prometheus.Metric, sessions []splunklib.AuthenticationSession) {
+	byUser := make(map[string]float64)
+	for _, s := range sessions {
+		byUser[s.Content.UserName]++
+	}
+	if len(byUser) > um.maxUsers {
+		level.Debug(um.logger).Log("msg", "too many users with sessions, summing those with the least", "users", len(byUser), "max", um.maxUsers)
+		byUser = capCounts(byUser, um.maxUsers, otherUser)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		um.sessionsDescriptor, prometheus.GaugeValue, float64(len(sessions)),
+	)
+	for user, count := range byUser {
+		ch <- prometheus.MustNewConstMetric(
+			um.userSessionsDescriptor, prometheus.GaugeValue, count, user,
+		)
+	}
+}
+
+// collectUsers sends the number of locked out users
+func (um *UsersManager) collectUsers(ch chan<- prometheus.Metric, users []splunklib.AuthenticationUser) {
+	lockedOut := 0
+	for _, u := range users {
+		if u.Content.LockedOut {
+			lockedOut++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(
+		um.lockedOutDescriptor, prometheus.GaugeValue, float64(lockedOut),
+	)
+}
+
+// collectTokens sends the expiration of enabled tokens
+// the expiration of the token used by the exporter is read from its claims, see collectTokenExpiry.
+func (um *UsersManager) collectTokens(ch chan<- prometheus.Metric, tokens []splunklib.AuthorizationToken) {
+	for _, t := range tokens {
+		c := t.Content
+		if c.Status != "enabled" || c.Claims.Expiration == 0 {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			um.tokenExpiryDescriptor, prometheus.GaugeValue, float64(c.Claims.Expiration), t.ID.Title, c.Claims.Subject, c.Claims.Audience,
+		)
+	}
+}
+
+// capCounts keeps the max keys with the highest counts, and sums the others under the other key
+func capCounts(counts map[string]float64, max int, other string) map[string]float64 {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	// highest first, by name for a stable result
+	slices.SortFunc(keys, func(a, b string) int {
+		if counts[a] != counts[b] {
+			if counts[a] > counts[b] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	capped := make(map[string]float64, max+1)
+	for _, k := range keys[:max] {
+		capped[k] = counts[k]
+	}
+	capped[other] = 0
+	for _, k := range keys[max:] {
+		capped[other] += counts[k]
+	}
+	return capped
+}
@@ -0,0 +1,70 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/K-Yo/splunk_exporter/config"
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/authentication/httpauth-tokens": "testdata/authenticationhttpauthtokens.json",
+		"/services/authentication/users":           "testdata/authenticationusers.json",
+		"/services/authorization/tokens":           "testdata/authorizationtokens.json",
+	})
+	um := newUsersManager(namespace, spk, log.NewNopLogger(), config.Users{MaxUsers: 2})
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = um.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_sessions Number of active user sessions, from authentication/httpauth-tokens API
+# TYPE splunk_exporter_sessions gauge
+splunk_exporter_sessions 4
+# HELP splunk_exporter_user_sessions Number of active sessions of a user, users over the configured limit are summed as "_other", from authentication/httpauth-tokens API
+# TYPE splunk_exporter_user_sessions gauge
+splunk_exporter_user_sessions{user="_other"} 1
+splunk_exporter_user_sessions{user="admin"} 2
+splunk_exporter_user_sessions{user="alice"} 1
+# HELP splunk_exporter_users_locked_out Number of users locked out after failed logins, from authentication/users API
+# TYPE splunk_exporter_users_locked_out gauge
+splunk_exporter_users_locked_out 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_sessions",
+		"splunk_exporter_user_sessions",
+		"splunk_exporter_users_locked_out",
+	))
+	assert.True(t, ok)
+}
+
+func TestUsersManager_Tokens(t *testing.T) {
+	um := newUsersManager(namespace, nil, log.NewNopLogger(), config.Users{})
+	tokens := readTestEntries[splunklib.AuthorizationToken](t, "testdata/authorizationtokens.json")
+
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		um.collectTokens(ch, tokens)
+	})
+
+	// tokens disabled or without expiration are not exported
+	expected := `
+# HELP splunk_exporter_token_expiry_timestamp_seconds Expiration time of an enabled authentication token, tokens without expiration are not exported, from authorization/tokens API
+# TYPE splunk_exporter_token_expiry_timestamp_seconds gauge
+splunk_exporter_token_expiry_timestamp_seconds{audience="splunk_exporter",id="9c37c67cfc34e9bb14e8cf24d9e41decb4571249b556cc471a522c02fd6e11fa",user="admin"} 1.717070456e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestCapCounts(t *testing.T) {
+	counts := map[string]float64{"a": 1, "b": 3, "c": 1, "d": 2}
+
+	assert.Equal(t, map[string]float64{"b": 3, "d": 2, "_other": 2}, capCounts(counts, 2, "_other"))
+}
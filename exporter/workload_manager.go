@@ -0,0 +1,154 @@
+package exporter
+
+import (
+	"strconv"
+
+	splunklib "github.com/K-Yo/splunk_exporter/splunk"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WorkloadManager collects workload management pools and rules, and what processes use in each pool
+// Splunk does not count how many searches each workload rule matched, only rule definitions are exported.
+type WorkloadManager struct {
+	splunk                    *splunklib.Splunk // Splunk client
+	logger                    log.Logger
+	cpuAllocatedDescriptor    *prometheus.Desc
+	memoryAllocatedDescriptor *prometheus.Desc
+	defaultPoolDescriptor     *prometheus.Desc
+	cpuUsageDescriptor        *prometheus.Desc
+	memoryUsageDescriptor     *prometheus.Desc
+	ruleInfoDescriptor        *prometheus.Desc
+}
+
+func newWorkloadManager(namespace string, spk *splunklib.Splunk, logger log.Logger) *WorkloadManager {
+
+	level.Debug(logger).Log("msg", "Initiating workload manager")
+
+	wm := WorkloadManager{
+		splunk: spk,
+		logger: logger,
+		cpuAllocatedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "workload", "pool_cpu_allocated_ratio"),
+			"Share of the CPU allocated to a workload pool, between 0 and 1, from workloads/status API",
+			[]string{"pool", "category"}, nil,
+		),
+		memoryAllocatedDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "workload", "pool_memory_allocated_ratio"),
+			"Share of the memory allocated to a workload pool, between 0 and 1, from workloads/status API",
+			[]string{"pool", "category"}, nil,
+		),
+		defaultPoolDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "workload", "pool_default"),
+			"Whether a workload pool is the default pool of its category, from workloads/pools API",
+			[]string{"pool", "category"}, nil,
+		),
+		cpuUsageDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "workload", "pool_cpu_usage_cores"),
+			"CPU cores used by processes of a workload pool during the last minute, from introspection data",
+			[]string{"host", "pool"}, nil,
+		),
+		memoryUsageDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "workload", "pool_memory_usage_bytes"),
+			"Memory used by processes of a workload pool during the last minute, from introspection data",
+			[]string{"host", "pool"}, nil,
+		),
+		ruleInfoDescriptor: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "workload", "rule_info"),
+			"Workload rule with its predicate, evaluation order, and the pool it places searches in or its action, from workloads/rules API",
+			[]string{"rule", "predicate", "pool", "action", "order"}, nil,
+		),
+	}
+
+	level.Debug(logger).Log("msg", "Done initiating workload manager")
+	return &wm
+}
+
+func (wm *WorkloadManager) CollectMeasures(ch chan<- prometheus.Metric) bool {
+	status := splunklib.WorkloadsStatus{}
+	if err := wm.splunk.Client.Read(&status); err != nil {
+		level.Error(wm.logger).Log("msg", "failed to read workload management status", "err", err)
+		return false
+	}
+	if !status.Content.General.IsSupported || !status.Content.General.Enabled {
+		level.Debug(wm.logger).Log("msg", "Workload management is disabled, skipping workload measures")
+		return true
+	}
+
+	level.Info(wm.logger).Log("msg", "Collecting Workload measures")
+	ret := true
+
+	wm.collectAllocations(ch, &status)
+
+	pools := make([]splunklib.WorkloadPool, 0)
+	if err := wm.splunk.ListAll(&pools, nil); err != nil {
+		level.Error(wm.logger).Log("msg", "failed to list workload pools", "err", err)
+		ret = false
+	} else {
+		wm.collectPools(ch, pools)
+	}
+
+	rules := make([]splunklib.WorkloadRule, 0)
+	if err := wm.splunk.ListAll(&rules, nil); err != nil {
+		level.Error(wm.logger).Log("msg", "failed to list workload rules", "err", err)
+		ret = false
+	} else {
+		wm.collectRules(ch, rules)
+	}
+
+	usages, err := wm.splunk.GetWorkloadPoolUsage()
+	if err != nil {
+		level.Error(wm.logger).Log("msg", "failed to get workload pool usage", "err", err)
+		ret = false
+	} else {
+		wm.collectUsage(ch, usages)
+	}
+
+	level.Info(wm.logger).Log("msg", "Done collecting Workload measures", "success", ret)
+	return ret
+}
+
+// collectAllocations sends the share of CPU and memory allocated to each pool
+func (wm *WorkloadManager) collectAllocations(ch chan<- prometheus.Metric, status *splunklib.WorkloadsStatus) {
+	for name, p := range status.Content.Pools {
+		ch <- prometheus.MustNewConstMetric(
+			wm.cpuAllocatedDescriptor, prometheus.GaugeValue, float64(p.CPUAllocatedPct)/100, name, p.Category,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			wm.memoryAllocatedDescriptor, prometheus.GaugeValue, float64(p.MemoryAllocatedPct)/100, name, p.Category,
+		)
+	}
+}
+
+// collectPools sends whether each pool is the default of its category
+func (wm *WorkloadManager) collectPools(ch chan<- prometheus.Metric, pools []splunklib.WorkloadPool) {
+	for _, p := range pools {
+		ch <- prometheus.MustNewConstMetric(
+			wm.defaultPoolDescriptor, prometheus.GaugeValue, boolToFloat(bool(p.Content.DefaultCategoryPool)), p.ID.Title, p.Content.Category,
+		)
+	}
+}
+
+// collectRules sends the definition of each rule
+func (wm *WorkloadManager) collectRules(ch chan<- prometheus.Metric, rules []splunklib.WorkloadRule) {
+	for _, r := range rules {
+		c := r.Content
+		order := strconv.FormatFloat(float64(c.Order), 'f', -1, 64)
+		ch <- prometheus.MustNewConstMetric(
+			wm.ruleInfoDescriptor, prometheus.GaugeValue, 1, splunklib.EntryName(r.ID), c.Predicate, c.WorkloadPool, c.Action, order,
+		)
+	}
+}
+
+// collectUsage sends CPU and memory used by each pool
+func (wm *WorkloadManager) collectUsage(ch chan<- prometheus.Metric, usages []splunklib.WorkloadPoolUsage) {
+	for _, u := range usages {
+		ch <- prometheus.MustNewConstMetric(
+			wm.cpuUsageDescriptor, prometheus.GaugeValue, u.CPUPct/100, u.Host, u.Pool,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			wm.memoryUsageDescriptor, prometheus.GaugeValue, u.MemoryBytes, u.Host, u.Pool,
+		)
+	}
+}
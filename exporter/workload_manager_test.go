@@ -0,0 +1,65 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadManager(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/workloads/status": "testdata/workloadsstatus.json",
+		"/services/workloads/pools":  "testdata/workloadspools.json",
+		"/services/workloads/rules":  "testdata/workloadsrules.json",
+		"/services/search/v2/jobs":   "testdata/searchworkloadusage.json",
+	})
+	wm := newWorkloadManager(namespace, spk, log.NewNopLogger())
+
+	var ok bool
+	c := testCollector(func(ch chan<- prometheus.Metric) {
+		ok = wm.CollectMeasures(ch)
+	})
+
+	expected := `
+# HELP splunk_exporter_workload_pool_cpu_allocated_ratio Share of the CPU allocated to a workload pool, between 0 and 1, from workloads/status API
+# TYPE splunk_exporter_workload_pool_cpu_allocated_ratio gauge
+splunk_exporter_workload_pool_cpu_allocated_ratio{category="ingest",pool="ingest_pool"} 0.2
+splunk_exporter_workload_pool_cpu_allocated_ratio{category="search",pool="limited_perf"} 0.21
+splunk_exporter_workload_pool_cpu_allocated_ratio{category="search",pool="standard_perf"} 0.49
+# HELP splunk_exporter_workload_pool_cpu_usage_cores CPU cores used by processes of a workload pool during the last minute, from introspection data
+# TYPE splunk_exporter_workload_pool_cpu_usage_cores gauge
+splunk_exporter_workload_pool_cpu_usage_cores{host="sh1",pool="limited_perf"} 0.12
+splunk_exporter_workload_pool_cpu_usage_cores{host="sh1",pool="standard_perf"} 2.505
+# HELP splunk_exporter_workload_pool_default Whether a workload pool is the default pool of its category, from workloads/pools API
+# TYPE splunk_exporter_workload_pool_default gauge
+splunk_exporter_workload_pool_default{category="ingest",pool="ingest_pool"} 1
+splunk_exporter_workload_pool_default{category="search",pool="limited_perf"} 0
+splunk_exporter_workload_pool_default{category="search",pool="standard_perf"} 1
+# HELP splunk_exporter_workload_rule_info Workload rule with its predicate, evaluation order, and the pool it places searches in or its action, from workloads/rules API
+# TYPE splunk_exporter_workload_rule_info gauge
+splunk_exporter_workload_rule_info{action="",order="1",pool="limited_perf",predicate="search_type=adhoc",rule="adhoc_to_limited"} 1
+splunk_exporter_workload_rule_info{action="abort",order="2",pool="",predicate="runtime>4h",rule="abort_long_searches"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"splunk_exporter_workload_pool_cpu_allocated_ratio",
+		"splunk_exporter_workload_pool_cpu_usage_cores",
+		"splunk_exporter_workload_pool_default",
+		"splunk_exporter_workload_rule_info",
+	))
+	assert.True(t, ok)
+}
+
+func TestWorkloadManager_Disabled(t *testing.T) {
+	spk := newTestdataSplunk(t, map[string]string{
+		"/services/workloads/status": "testdata/workloadsstatus-disabled.json",
+	})
+	wm := newWorkloadManager(namespace, spk, log.NewNopLogger())
+
+	ch := make(chan prometheus.Metric, 100)
+	assert.True(t, wm.CollectMeasures(ch))
+	assert.Empty(t, ch)
+}
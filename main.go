@@ -36,6 +36,17 @@ var (
 	externalURL  = kingpin.Flag("web.external-url", "The URL under which Splunk exporter is externally reachable (for example, if Splunk exporter is served via a reverse proxy). Used for generating relative and absolute links back to Splunk exporter itself. If the URL has a path portion, it will be used to prefix all HTTP endpoints served by splunk exporter. If omitted, relevant URL components will be derived automatically.").PlaceHolder("<url>").String()
 	routePrefix  = kingpin.Flag("web.route-prefix", "Prefix for the internal routes of web endpoints. Defaults to path of --web.external-url.").PlaceHolder("<path>").String()
 	toolkitFlags = webflag.AddFlags(kingpin.CommandLine, ":9115")
+
+	serveCmd = kingpin.Command("serve", "Run the exporter HTTP server.").Default()
+
+	discoverCmd    = kingpin.Command("discover", "List metrics available in a Splunk metrics index, with their dimensions and cardinality.")
+	discoverIndex  = discoverCmd.Flag("index", "Metrics index to look into.").Default("_metrics").String()
+	discoverMatch  = discoverCmd.Flag("match", "Only list metrics whose name matches this pattern, Splunk wildcards are accepted (for example spl.intr.*).").Default("*").String()
+	discoverAsYAML = discoverCmd.Flag("yaml", "Print a \"metrics:\" configuration snippet instead of a table.").Bool()
+
+	collectCmd        = kingpin.Command("collect", "Scrape the Splunk instance once, print the metrics and exit with a non-zero code if any collector failed.")
+	collectCollectors = collectCmd.Flag("collector", "Only run this collector, can be repeated. Defaults to all collectors.").Strings()
+	collectFormat     = collectCmd.Flag("format", "Output format.").Default("text").Enum("text", "json")
 )
 
 func init() {
@@ -56,7 +67,7 @@ func run() int {
 	flag.AddFlags(kingpin.CommandLine, promlogConfig)
 	kingpin.Version(version.Print("splunk_exporter"))
 	kingpin.HelpFlag.Short('h')
-	kingpin.Parse()
+	command := kingpin.Parse()
 	logger := promlog.New(promlogConfig)
 
 	level.Info(logger).Log("msg", "Starting splunk_exporter", "version", version.Info())
@@ -67,15 +78,15 @@ func run() int {
 		return 1
 	}
 
-	// register exporter
-	opts := exporter.SplunkOpts{
-		URI:      sc.C.URL,
-		Token:    sc.C.Token,
-		Username: sc.C.Username,
-		Password: sc.C.Password,
-		Insecure: sc.C.Insecure,
+	switch command {
+	case discoverCmd.FullCommand():
+		return discover(logger)
+	case collectCmd.FullCommand():
+		return collect(logger)
 	}
-	exp, err := exporter.New(opts, logger, sc.C.Metrics)
+
+	// register exporter
+	exp, err := exporter.New(splunkOpts(sc.C), logger, sc.C.Metrics, sc.C.Collectors)
 	if err != nil {
 		level.Error(logger).Log("msg", "could not create exporter", "err", err)
 		return 1
@@ -213,6 +224,17 @@ func run() int {
 
 }
 
+// splunkOpts extracts Splunk connection parameters from configuration
+func splunkOpts(c *config.Config) exporter.SplunkOpts {
+	return exporter.SplunkOpts{
+		URI:      c.URL,
+		Token:    c.Token,
+		Username: c.Username,
+		Password: c.Password,
+		Insecure: c.Insecure,
+	}
+}
+
 func startsOrEndsWithQuote(s string) bool {
 	return strings.HasPrefix(s, "\"") || strings.HasPrefix(s, "'") ||
 		strings.HasSuffix(s, "\"") || strings.HasSuffix(s, "'")
@@ -1,5 +1,7 @@
 package splunk
 
+import "encoding/json"
+
 type SearchAPIResult struct {
 	Results     []map[string]string `json:"results"`
 	Fields      []APIField          `json:"fields"`
@@ -12,3 +14,16 @@ type SearchAPIResult struct {
 type APIField struct {
 	Name string `json:"name"`
 }
+
+// Paging tells which part of all available entries a REST API list response holds
+type Paging struct {
+	Total   int `json:"total"`
+	PerPage int `json:"perPage"`
+	Offset  int `json:"offset"`
+}
+
+// ListAPIResult is the envelope of a REST API list response, entries are left to be decoded by caller
+type ListAPIResult struct {
+	Entry  json.RawMessage `json:"entry"`
+	Paging Paging          `json:"paging"`
+}
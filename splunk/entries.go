@@ -1,6 +1,12 @@
 package splunk
 
-import "github.com/splunk/go-splunk-client/pkg/client"
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/splunk/go-splunk-client/pkg/client"
+)
 
 type FeatureHealth struct {
 	Health   string                   `json:"health"`
@@ -36,8 +42,666 @@ type ServerIntrospectionIndexer struct {
 	Content ServerIntrospectionIndexerContent `json:"content"`
 }
 
+type ServerIntrospectionQueueContent struct {
+	CurrentSize      Number `json:"current_size"`       // Number of events in the queue.
+	CurrentSizeBytes Number `json:"current_size_bytes"` // Size of events in the queue.
+	LargestSize      Number `json:"largest_size"`       // Largest number of events seen in the queue since startup.
+	MaxSizeBytes     Number `json:"max_size_bytes"`     // Capacity of the queue.
+}
+
+// ServerIntrospectionQueue https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTintrospect#server.2Fintrospection.2Fqueues
+type ServerIntrospectionQueue struct {
+	ID      client.ID                       `selective:"create" service:"server/introspection/queues"`
+	Content ServerIntrospectionQueueContent `json:"content"`
+}
+
+type DataIndexContent struct {
+	CurrentDBSizeMB        Number `json:"currentDBSizeMB"`
+	Datatype               string `json:"datatype"` // event or metric
+	Disabled               Bool   `json:"disabled"`
+	FrozenTimePeriodInSecs Number `json:"frozenTimePeriodInSecs"` // Retention period.
+	MaxTime                Time   `json:"maxTime"`                // Timestamp of the newest event, zero when index is empty.
+	MaxTotalDataSizeMB     Number `json:"maxTotalDataSizeMB"`
+	MinTime                Time   `json:"minTime"`    // Timestamp of the oldest event, zero when index is empty.
+	RemotePath             string `json:"remotePath"` // Remote storage of a SmartStore index, empty otherwise.
+	TotalEventCount        Number `json:"totalEventCount"`
+
+	Fields map[string]interface{} `json:"-"` // Every field returned by Splunk, including those not mapped above.
+}
+
+func (c *DataIndexContent) UnmarshalJSON(data []byte) error {
+	type typed DataIndexContent // same fields, without this UnmarshalJSON method
+	var t typed
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*c = DataIndexContent(t)
+	c.Fields = fields
+	return nil
+}
+
 // DataIndex https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTintrospect#data.2Findexes
 type DataIndex struct {
-	ID      client.ID              `selective:"create" service:"data/indexes"`
-	Content map[string]interface{} `json:"content"`
+	ID      client.ID        `selective:"create" service:"data/indexes"`
+	Content DataIndexContent `json:"content"`
+}
+
+type DataIndexExtendedHomeDir struct {
+	EventCount      Number `json:"event_count"`
+	HotBucketCount  Number `json:"hot_bucket_count"`
+	WarmBucketCount Number `json:"warm_bucket_count"`
+}
+
+type DataIndexExtendedColdDir struct {
+	BucketCount Number `json:"bucket_count"`
+	EventCount  Number `json:"event_count"`
+}
+
+type DataIndexExtendedContent struct {
+	BucketDirs struct {
+		Home DataIndexExtendedHomeDir `json:"home"`
+		Cold DataIndexExtendedColdDir `json:"cold"`
+	} `json:"bucket_dirs"`
+}
+
+// DataIndexExtended is like DataIndex, with details about buckets
+// It is the endpoint the monitoring console uses for its index detail dashboards.
+type DataIndexExtended struct {
+	ID      client.ID                `selective:"create" service:"data/indexes-extended"`
+	Content DataIndexExtendedContent `json:"content"`
+}
+
+// ACL tells which app and user a knowledge object or search job belongs to
+type ACL struct {
+	App   string `json:"app"`
+	Owner string `json:"owner"`
+}
+
+type LicenserPoolContent struct {
+	Description    string  `json:"description"`
+	EffectiveQuota float64 `json:"effective_quota"` // Quota in bytes, resolves "MAX" to the stack quota.
+	StackID        string  `json:"stack_id"`
+	UsedBytes      float64 `json:"used_bytes"` // Bytes indexed today in this pool.
+}
+
+// LicenserPool https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTlicense#licenser.2Fpools
+type LicenserPool struct {
+	ID      client.ID           `selective:"create" service:"licenser/pools"`
+	Content LicenserPoolContent `json:"content"`
+}
+
+type LicenserStackContent struct {
+	Label string  `json:"label"`
+	Quota float64 `json:"quota"` // Quota in bytes.
+	Type  string  `json:"type"`  // License type, for example enterprise, forwarder, free.
+}
+
+// LicenserStack https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTlicense#licenser.2Fstacks
+type LicenserStack struct {
+	ID      client.ID            `selective:"create" service:"licenser/stacks"`
+	Content LicenserStackContent `json:"content"`
+}
+
+type LicenserUsageContent struct {
+	PeersUsageBytes float64 `json:"peers_usage_bytes"` // Bytes indexed today by all peers.
+	Quota           float64 `json:"quota"`             // Total quota in bytes.
+}
+
+// LicenserUsage https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTlicense#licenser.2Fusage
+type LicenserUsage struct {
+	ID      client.ID            `selective:"create" service:"licenser/usage"`
+	Content LicenserUsageContent `json:"content"`
+}
+
+type LicenserMessageContent struct {
+	Category    string `json:"category"` // For example license_window, pool_over_quota, orphan_peer.
+	CreateTime  int64  `json:"create_time"`
+	Description string `json:"description"`
+	PoolID      string `json:"pool_id"`
+	Severity    string `json:"severity"` // One of INFO, WARN, ERROR.
+	StackID     string `json:"stack_id"`
+}
+
+// LicenserMessage https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTlicense#licenser.2Fmessages
+type LicenserMessage struct {
+	ID      client.ID              `selective:"create" service:"licenser/messages"`
+	Content LicenserMessageContent `json:"content"`
+}
+
+type ServerInfoContent struct {
+	Build        string   `json:"build"`
+	GUID         string   `json:"guid"`
+	LicenseState string   `json:"licenseState"` // For example OK or EXPIRED.
+	OSName       string   `json:"os_name"`
+	ServerName   string   `json:"serverName"`
+	ServerRoles  []string `json:"server_roles"` // For example indexer, search_head, cluster_manager, license_manager.
+	StartupTime  Number   `json:"startup_time"` // epoch seconds
+	Version      string   `json:"version"`
+}
+
+// ServerInfo https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsystem#server.2Finfo
+type ServerInfo struct {
+	ID      client.ID         `selective:"create" service:"server/info"`
+	Content ServerInfoContent `json:"content"`
+}
+
+type ClusterManagerInfoContent struct {
+	InitializedFlag    Bool `json:"initialized_flag"`
+	MaintenanceMode    Bool `json:"maintenance_mode"`
+	RollingRestartFlag Bool `json:"rolling_restart_flag"`
+	ServiceReadyFlag   Bool `json:"service_ready_flag"`
+}
+
+// ClusterManagerInfo https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTcluster#cluster.2Fmanager.2Finfo
+type ClusterManagerInfo struct {
+	ID      client.ID                 `selective:"create" service:"cluster/manager/info"`
+	Content ClusterManagerInfoContent `json:"content"`
+}
+
+type ClusterManagerGenerationContent struct {
+	GenerationID         Number `json:"generation_id"`
+	ReplicationFactorMet Bool   `json:"replication_factor_met"`
+	SearchFactorMet      Bool   `json:"search_factor_met"`
+}
+
+// ClusterManagerGeneration https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTcluster#cluster.2Fmanager.2Fgeneration
+type ClusterManagerGeneration struct {
+	ID      client.ID                       `selective:"create" service:"cluster/manager/generation"`
+	Content ClusterManagerGenerationContent `json:"content"`
+}
+
+type ClusterManagerPeerContent struct {
+	BucketCount  Number `json:"bucket_count"`
+	IsSearchable Bool   `json:"is_searchable"`
+	Label        string `json:"label"` // Server name of the peer.
+	PrimaryCount Number `json:"primary_count"`
+	Site         string `json:"site"`
+	Status       string `json:"status"` // For example Up, Down, Pending, Restarting, AutomaticDetention.
+}
+
+// ClusterManagerPeer https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTcluster#cluster.2Fmanager.2Fpeers
+type ClusterManagerPeer struct {
+	ID      client.ID                 `selective:"create" service:"cluster/manager/peers"`
+	Content ClusterManagerPeerContent `json:"content"`
+}
+
+// ClusterManagerFixup https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTcluster#cluster.2Fmanager.2Ffixup
+// There is one entry per bucket needing a fixup, requests must specify the fixup "level".
+type ClusterManagerFixup struct {
+	ID client.ID `selective:"create" service:"cluster/manager/fixup"`
+}
+
+type SHClusterCaptainInfoContent struct {
+	ElectedCaptain     Number `json:"elected_captain"` // Timestamp of the captain election.
+	ID                 string `json:"id"`              // GUID of the captain.
+	Label              string `json:"label"`           // Server name of the captain.
+	MaintenanceMode    Bool   `json:"maintenance_mode"`
+	MgmtURI            string `json:"mgmt_uri"`
+	RollingRestartFlag Bool   `json:"rolling_restart_flag"`
+	ServiceReadyFlag   Bool   `json:"service_ready_flag"`
+}
+
+// SHClusterCaptainInfo https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTcluster#shcluster.2Fcaptain.2Finfo
+type SHClusterCaptainInfo struct {
+	ID      client.ID                   `selective:"create" service:"shcluster/captain/info"`
+	Content SHClusterCaptainInfoContent `json:"content"`
+}
+
+type SHClusterCaptainMemberContent struct {
+	ArtifactCount    Number `json:"artifact_count"`
+	Label            string `json:"label"` // Server name of the member.
+	LastHeartbeat    Number `json:"last_heartbeat"`
+	PendingJobCount  Number `json:"pending_job_count"`
+	ReplicationCount Number `json:"replication_count"` // Number of artifact replications in progress.
+	Site             string `json:"site"`
+	Status           string `json:"status"` // For example Up, Down, Restarting, Detention.
+}
+
+// SHClusterCaptainMember https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTcluster#shcluster.2Fcaptain.2Fmembers
+type SHClusterCaptainMember struct {
+	ID      client.ID                     `selective:"create" service:"shcluster/captain/members"`
+	Content SHClusterCaptainMemberContent `json:"content"`
+}
+
+type SHClusterMemberInfoContent struct {
+	ActiveHistoricalSearchCount Number `json:"active_historical_search_count"`
+	ActiveRealtimeSearchCount   Number `json:"active_realtime_search_count"`
+	IsRegistered                Bool   `json:"is_registered"`
+	NoArtifactReplications      Bool   `json:"no_artifact_replications"`
+	RestartState                string `json:"restart_state"` // NoRestart when no restart is pending.
+	Status                      string `json:"status"`
+}
+
+// SHClusterMemberInfo https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTcluster#shcluster.2Fmember.2Finfo
+type SHClusterMemberInfo struct {
+	ID      client.ID                  `selective:"create" service:"shcluster/member/info"`
+	Content SHClusterMemberInfoContent `json:"content"`
+}
+
+type DataInputTCPCookedContent struct {
+	Disabled Bool `json:"disabled"`
+}
+
+// DataInputTCPCooked https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTinput#data.2Finputs.2Ftcp.2Fcooked
+// There is one entry per port receiving data from forwarders, the entry title is the port.
+type DataInputTCPCooked struct {
+	ID      client.ID                 `selective:"create" service:"data/inputs/tcp/cooked"`
+	Content DataInputTCPCookedContent `json:"content"`
+}
+
+type DeploymentServerClientApplication struct {
+	Result        string   `json:"result"` // outcome of the last deployment of the app, "Ok" when it succeeded
+	ServerClasses []string `json:"serverclasses"`
+}
+
+type DeploymentServerClientContent struct {
+	Applications      map[string]DeploymentServerClientApplication `json:"applications"`
+	Hostname          string                                       `json:"hostname"`
+	LastPhoneHomeTime Number                                       `json:"lastPhoneHomeTime"` // epoch seconds
+	ServerClasses     map[string]struct{}                          `json:"serverClasses"`
+	SplunkVersion     string                                       `json:"splunkVersion"`
+}
+
+// DeploymentServerClient https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTdeploy#deployment.2Fserver.2Fclients
+type DeploymentServerClient struct {
+	ID      client.ID                     `selective:"create" service:"deployment/server/clients"`
+	Content DeploymentServerClientContent `json:"content"`
+}
+
+// DeploymentServerClass https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTdeploy#deployment.2Fserver.2Fserverclasses
+type DeploymentServerClass struct {
+	ID client.ID `selective:"create" service:"deployment/server/serverclasses"`
+}
+
+type SavedSearchContent struct {
+	CronSchedule         string   `json:"cron_schedule"`
+	Disabled             Bool     `json:"disabled"`
+	DispatchEarliestTime string   `json:"dispatch.earliest_time"` // Relative time like -24h@h.
+	DispatchLatestTime   string   `json:"dispatch.latest_time"`
+	IsScheduled          Bool     `json:"is_scheduled"`
+	ScheduledTimes       []Number `json:"scheduled_times"` // Epoch seconds of the dispatches between the earliest_time and latest_time of the request.
+}
+
+// SavedSearch https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsearch#saved.2Fsearches
+type SavedSearch struct {
+	ID      client.ID          `selective:"create" service:"saved/searches"`
+	ACL     ACL                `json:"acl"`
+	Content SavedSearchContent `json:"content"`
+}
+
+type KVStoreStatusCurrent struct {
+	Disabled          Bool   `json:"disabled"`
+	ReplicationStatus string `json:"replicationStatus"` // For example "KV store captain" or "Non-captain KV store member".
+	Status            string `json:"status"`            // For example ready, starting or failed.
+	StorageEngine     string `json:"storageEngine"`
+}
+
+type KVStoreStatusMember struct {
+	HostAndPort       string `json:"hostAndPort"`
+	ReplicationStatus string `json:"replicationStatus"`
+}
+
+type KVStoreStatusContent struct {
+	Current KVStoreStatusCurrent           `json:"current"`
+	Members map[string]KVStoreStatusMember `json:"members"` // Members of the KV store replica set, by GUID.
+}
+
+// KVStoreStatus https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTkvstore#kvstore.2Fstatus
+type KVStoreStatus struct {
+	ID      client.ID            `selective:"create" service:"kvstore/status"`
+	Content KVStoreStatusContent `json:"content"`
+}
+
+// KVStoreCollectionStat holds MongoDB statistics of one KV store collection
+type KVStoreCollectionStat struct {
+	NS          string `json:"ns"` // Namespace of the collection, "<app>.<collection>".
+	Count       Number `json:"count"`
+	Size        Number `json:"size"`
+	StorageSize Number `json:"storageSize"`
+}
+
+type KVStoreCollectionStatsContent struct {
+	Data []string `json:"data"` // One JSON document per collection, see KVStoreCollectionStat.
+}
+
+// Collections decodes the statistics of each collection
+func (c KVStoreCollectionStatsContent) Collections() ([]KVStoreCollectionStat, error) {
+	stats := make([]KVStoreCollectionStat, 0, len(c.Data))
+	for _, d := range c.Data {
+		var s KVStoreCollectionStat
+		if err := json.Unmarshal([]byte(d), &s); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// KVStoreCollectionStats https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTintrospect#server.2Fintrospection.2Fkvstore.2Fcollectionstats
+type KVStoreCollectionStats struct {
+	ID      client.ID                     `selective:"create" service:"server/introspection/kvstore/collectionstats"`
+	Content KVStoreCollectionStatsContent `json:"content"`
+}
+
+type KVStoreServerStatusContent struct {
+	Opcounters map[string]Number `json:"opcounters"` // Operations since startup by type: insert, query, update, delete, getmore, command.
+}
+
+// KVStoreServerStatus https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTintrospect#server.2Fintrospection.2Fkvstore.2Fserverstatus
+type KVStoreServerStatus struct {
+	ID      client.ID                  `selective:"create" service:"server/introspection/kvstore/serverstatus"`
+	Content KVStoreServerStatusContent `json:"content"`
+}
+
+// DataInputHTTPContent purposely leaves the token value out, it is a secret.
+type DataInputHTTPContent struct {
+	Disabled Bool     `json:"disabled"`
+	Index    string   `json:"index"`   // Default index of events.
+	Indexes  []string `json:"indexes"` // Indexes allowed, any index when empty.
+}
+
+// DataInputHTTP https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTinput#data.2Finputs.2Fhttp
+// There is one entry per HEC token, titled "http://<token name>".
+type DataInputHTTP struct {
+	ID      client.ID            `selective:"create" service:"data/inputs/http"`
+	Content DataInputHTTPContent `json:"content"`
+}
+
+type MessageContent struct {
+	Message     string `json:"message"`
+	Severity    string `json:"severity"` // info, warn or error
+	TimeCreated Number `json:"timeCreated_epochSecs"`
+}
+
+// Message https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsystem#messages
+// Messages are shown in the bulletin menu of Splunk Web, the entry title is the message name.
+type Message struct {
+	ID      client.ID      `selective:"create" service:"messages"`
+	Content MessageContent `json:"content"`
+}
+
+type ServerResourceUsageHostContent struct {
+	CPUCount              Number `json:"cpu_count"`
+	CPUIdlePct            Number `json:"cpu_idle_pct"`
+	CPUSystemPct          Number `json:"cpu_system_pct"`
+	CPUUserPct            Number `json:"cpu_user_pct"`
+	Mem                   Number `json:"mem"`      // MB
+	MemUsed               Number `json:"mem_used"` // MB
+	NormalizedLoadAvg1Min Number `json:"normalized_load_avg_1min"`
+	Swap                  Number `json:"swap"`      // MB
+	SwapUsed              Number `json:"swap_used"` // MB
+}
+
+// ServerResourceUsageHost https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTintrospect#server.2Fstatus.2Fresource-usage.2Fhostwide
+type ServerResourceUsageHost struct {
+	ID      client.ID                      `selective:"create" service:"server/status/resource-usage/hostwide"`
+	Content ServerResourceUsageHostContent `json:"content"`
+}
+
+type ServerPartitionSpaceContent struct {
+	Capacity   Number `json:"capacity"` // MB
+	Free       Number `json:"free"`     // MB
+	FSType     string `json:"fs_type"`
+	MountPoint string `json:"mount_point"`
+}
+
+// ServerPartitionSpace https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTintrospect#server.2Fstatus.2Fpartitions-space
+// There is one entry per partition used by Splunk.
+type ServerPartitionSpace struct {
+	ID      client.ID                   `selective:"create" service:"server/status/partitions-space"`
+	Content ServerPartitionSpaceContent `json:"content"`
+}
+
+type ServerSettingsContent struct {
+	MinFreeSpace string `json:"minFreeSpace"` // In MB, or a percentage of partition capacity like "5%".
+}
+
+// ServerSettings https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsystem#server.2Fsettings
+type ServerSettings struct {
+	ID      client.ID             `selective:"create" service:"server/settings"`
+	Content ServerSettingsContent `json:"content"`
+}
+
+// SummarizedReport is a report using a report acceleration summary
+type SummarizedReport struct {
+	Owner string
+	App   string
+	Name  string
+}
+
+type SummarizationContent struct {
+	Buckets       Number             `json:"summary.buckets"`
+	Complete      Number             `json:"summary.complete"` // Between 0 and 1.
+	ModTime       Number             `json:"summary.mod_time"` // Last update of the summary.
+	Size          Number             `json:"summary.size"`     // In bytes.
+	SavedSearches []SummarizedReport `json:"-"`                // Reports sharing a report acceleration summary, sorted.
+}
+
+func (c *SummarizationContent) UnmarshalJSON(data []byte) error {
+	type typed SummarizationContent // same fields, without this UnmarshalJSON method
+	var t typed
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	// reports are keyed by owner, app and name, like "saved_searches.admin;search;Errors by host.name"
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*c = SummarizationContent(t)
+	for k, v := range fields {
+		name, ok := v.(string)
+		if !ok || !strings.HasPrefix(k, "saved_searches.") || !strings.HasSuffix(k, ".name") {
+			continue
+		}
+		key := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(k, "saved_searches."), ".name"), ";", 3)
+		if len(key) != 3 {
+			continue
+		}
+		c.SavedSearches = append(c.SavedSearches, SummarizedReport{Owner: key[0], App: key[1], Name: name})
+	}
+	slices.SortFunc(c.SavedSearches, func(a, b SummarizedReport) int {
+		return strings.Compare(a.Owner+";"+a.App+";"+a.Name, b.Owner+";"+b.App+";"+b.Name)
+	})
+	return nil
+}
+
+// Summarization https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTknowledge#admin.2Fsummarization
+// Data model summaries are listed with by_tstats, their title is "tstats:DM_<app>_<model>".
+// Report acceleration summaries are listed otherwise, their title is the summary ID.
+type Summarization struct {
+	ID      client.ID            `selective:"create" service:"admin/summarization"`
+	ACL     ACL                  `json:"acl"`
+	Content SummarizationContent `json:"content"`
+}
+
+// CacheManagerBucket is a bucket known by the SmartStore cache manager, only the number of them is used.
+// admin/cacheman is not in the REST API reference, it exists on indexers with SmartStore indexes.
+type CacheManagerBucket struct {
+	ID client.ID `selective:"create" service:"admin/cacheman"`
+}
+
+type WorkloadsStatusGeneral struct {
+	Enabled     Bool `json:"enabled"`
+	IsSupported Bool `json:"isSupported"`
+}
+
+type WorkloadsStatusPool struct {
+	Category           string `json:"category"`
+	CPUAllocatedPct    Number `json:"cpu_allocated_percent"`
+	MemoryAllocatedPct Number `json:"mem_allocated_percent"`
+}
+
+type WorkloadsStatusContent struct {
+	General WorkloadsStatusGeneral         `json:"general"`
+	Pools   map[string]WorkloadsStatusPool `json:"workload-pools"`
+}
+
+// WorkloadsStatus https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTworkloads#workloads.2Fstatus
+type WorkloadsStatus struct {
+	ID      client.ID              `selective:"create" service:"workloads/status"`
+	Content WorkloadsStatusContent `json:"content"`
+}
+
+type WorkloadPoolContent struct {
+	Category            string `json:"category"` // search, ingest or misc
+	CPUWeight           Number `json:"cpu_weight"`
+	DefaultCategoryPool Bool   `json:"default_category_pool"`
+	MemoryWeight        Number `json:"mem_weight"`
+}
+
+// WorkloadPool https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTworkloads#workloads.2Fpools
+type WorkloadPool struct {
+	ID      client.ID           `selective:"create" service:"workloads/pools"`
+	Content WorkloadPoolContent `json:"content"`
+}
+
+type WorkloadRuleContent struct {
+	Action       string `json:"action"` // Empty when the rule places searches in a pool.
+	Order        Number `json:"order"`
+	Predicate    string `json:"predicate"`
+	WorkloadPool string `json:"workload_pool"`
+}
+
+// WorkloadRule https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTworkloads#workloads.2Frules
+type WorkloadRule struct {
+	ID      client.ID           `selective:"create" service:"workloads/rules"`
+	Content WorkloadRuleContent `json:"content"`
+}
+
+type AppLocalContent struct {
+	Disabled      Bool   `json:"disabled"`
+	UpdateVersion string `json:"update.version"` // Only set when Splunkbase has a newer version.
+	Version       string `json:"version"`
+	Visible       Bool   `json:"visible"`
+}
+
+// AppLocal https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTapps#apps.2Flocal
+type AppLocal struct {
+	ID      client.ID       `selective:"create" service:"apps/local"`
+	Content AppLocalContent `json:"content"`
+}
+
+type AuthenticationSessionContent struct {
+	UserName string `json:"userName"` // The session key in authString is a secret, it is never decoded.
+}
+
+// AuthenticationSession https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTaccess#authentication.2Fhttpauth-tokens
+type AuthenticationSession struct {
+	ID      client.ID                    `selective:"create" service:"authentication/httpauth-tokens"`
+	Content AuthenticationSessionContent `json:"content"`
+}
+
+type AuthenticationUserContent struct {
+	LockedOut Bool `json:"locked-out"`
+}
+
+// AuthenticationUser https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTaccess#authentication.2Fusers
+type AuthenticationUser struct {
+	ID      client.ID                 `selective:"create" service:"authentication/users"`
+	Content AuthenticationUserContent `json:"content"`
+}
+
+type AuthorizationTokenClaims struct {
+	Audience   string `json:"aud"`
+	Expiration Number `json:"exp"` // 0 when the token never expires.
+	Subject    string `json:"sub"` // User of the token.
+}
+
+type AuthorizationTokenContent struct {
+	Claims AuthorizationTokenClaims `json:"claims"`
+	Status string                   `json:"status"` // enabled or disabled
+}
+
+// AuthorizationToken https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTaccess#authorization.2Ftokens
+// The entry title is the token ID, the jti claim of the token. The token itself is never returned.
+type AuthorizationToken struct {
+	ID      client.ID                 `selective:"create" service:"authorization/tokens"`
+	Content AuthorizationTokenContent `json:"content"`
+}
+
+type DistributedPeerContent struct {
+	BundleVersions    []Number `json:"bundle_versions"` // Creation time of the knowledge bundles the peer has.
+	Disabled          Bool     `json:"disabled"`
+	GUID              string   `json:"guid"`
+	LastHeartbeat     Number   `json:"last_heartbeat"`
+	PeerName          string   `json:"peerName"`
+	ReplicationStatus string   `json:"replicationStatus"` // For example Successful, Failed or In Progress.
+	Status            string   `json:"status"`            // Up, Down or Sick
+	Version           string   `json:"version"`
+}
+
+// DistributedPeer https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsearch#search.2Fdistributed.2Fpeers
+// The entry title is the management URI of the peer, like idx1:8089.
+type DistributedPeer struct {
+	ID      client.ID              `selective:"create" service:"search/distributed/peers"`
+	Content DistributedPeerContent `json:"content"`
+}
+
+type BundleReplicationFileContent struct {
+	Checksum  string `json:"checksum"`
+	Size      Number `json:"size"`      // In bytes.
+	Timestamp Number `json:"timestamp"` // Creation time of the bundle.
+}
+
+// BundleReplicationFile https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsearch#search.2Fdistributed.2Fbundle-replication-files
+// It is a knowledge bundle of the search head, the entry title is its file name.
+type BundleReplicationFile struct {
+	ID      client.ID                    `selective:"create" service:"search/distributed/bundle-replication-files"`
+	Content BundleReplicationFileContent `json:"content"`
+}
+
+type FiredAlertContent struct {
+	TriggeredAlertCount Number `json:"triggered_alert_count"`
+}
+
+// FiredAlert https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsearch#alerts.2Ffired_alerts
+// The entry title is the alert name, "-" is the sum over all alerts.
+type FiredAlert struct {
+	ID      client.ID         `selective:"create" service:"alerts/fired_alerts"`
+	Content FiredAlertContent `json:"content"`
+}
+
+type TriggeredAlertContent struct {
+	SavedSearchName string `json:"savedsearch_name"`
+	Severity        Number `json:"severity"` // 1 for info to 5 for critical
+	SID             string `json:"sid"`
+	TriggerTime     Number `json:"trigger_time"`
+}
+
+// TriggeredAlert https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsearch#alerts.2Ffired_alerts.2F.7Bname.7D
+// It is listed under the name of its alert, with ListAllIn.
+type TriggeredAlert struct {
+	ID      client.ID             `selective:"create" service:"alerts/fired_alerts"`
+	Content TriggeredAlertContent `json:"content"`
+}
+
+type SearchJobContent struct {
+	DispatchState string `json:"dispatchState"` // QUEUED, PARSING, RUNNING, FINALIZING, PAUSED, DONE or FAILED
+}
+
+// SearchJob https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTsearch#search.2Fv2.2Fjobs
+// The entry title is the search ID.
+type SearchJob struct {
+	ID      client.ID        `selective:"create" service:"search/v2/jobs"`
+	ACL     ACL              `json:"acl"`
+	Content SearchJobContent `json:"content"`
+}
+
+type ServerSearchConcurrencyContent struct {
+	MaxAutoSummarySearches   Number `json:"max_auto_summary_searches"`
+	MaxHistScheduledSearches Number `json:"max_hist_scheduled_searches"`
+	MaxHistSearches          Number `json:"max_hist_searches"`
+	MaxRTScheduledSearches   Number `json:"max_rt_scheduled_searches"`
+	MaxRTSearches            Number `json:"max_rt_searches"`
+}
+
+// ServerSearchConcurrency https://docs.splunk.com/Documentation/Splunk/9.2.1/RESTREF/RESTintrospect#server.2Fstatus.2Flimits.2Fsearch-concurrency
+type ServerSearchConcurrency struct {
+	ID      client.ID                      `selective:"create" service:"server/status/limits/search-concurrency"`
+	Content ServerSearchConcurrencyContent `json:"content"`
 }
@@ -30,3 +30,97 @@ func dimensionsQuery(index string, metric string) string {
 		| mvexpand dims`,
 		index, metric)
 }
+
+// metricNamesQuery lists metric names of an index matching a (possibly wildcarded) pattern
+func metricNamesQuery(index string, match string) string {
+	return fmt.Sprintf(`
+		| mcatalog values(metric_name) as metric_name
+		  where index="%s" metric_name="%s"
+		| mvexpand metric_name
+		| sort metric_name`,
+		index, match)
+}
+
+// cardinalityQuery counts the distinct time series (dimension combinations) seen for one metric in the last 24 hours
+func cardinalityQuery(index string, metric string) string {
+	return fmt.Sprintf(`
+		| mstats count(_value)
+		  where index="%s" metric_name="%s" earliest=-24h
+		  by _timeseries
+		| stats count as cardinality`,
+		index, metric)
+}
+
+// forwarderConnectionsQuery summarizes forwarder connections received by indexers over the last 24 hours, from metrics.log
+// forwarders are sorted from the most recently seen, total is the number of forwarders before truncating to max.
+func forwarderConnectionsQuery(max int) string {
+	return fmt.Sprintf(`
+		search index=_internal source=*metrics.log* group=tcpin_connections earliest=-24h
+		| stats latest(_time) as last_seen
+		        latest(tcp_KBps) as kbps
+		        latest(version) as version
+		        latest(fwdType) as fwd_type
+		        latest(sourceIp) as source_ip
+		  by hostname
+		| eventstats count as total
+		| sort 0 - last_seen
+		| head %d`,
+		max)
+}
+
+// schedulerActivityQuery summarizes scheduled search runs of the last hour by saved search, from scheduler logs
+// searches are sorted from the most skipped, total is the number of saved searches before truncating to max.
+func schedulerActivityQuery(max int) string {
+	return fmt.Sprintf(`
+		search index=_internal sourcetype=scheduler earliest=-1h status=*
+		| stats count(eval(status="skipped")) as skipped
+		        count(eval(status="deferred")) as deferred
+		        count(eval(status="success" OR status="delegated_remote_completion")) as success
+		        avg(run_time) as run_time
+		  by app savedsearch_name
+		| eventstats count as total
+		| sort 0 - skipped - deferred - success
+		| head %d`,
+		max)
+}
+
+// hecTokenActivityQuery sums HTTP Event Collector activity of the last 5 minutes by token, from introspection data
+func hecTokenActivityQuery() string {
+	return `
+		search index=_introspection component=HttpEventCollector data.series="http_event_collector_token" earliest=-5m
+		| rename data.* as *
+		| stats sum(total_bytes_received) as bytes
+		        sum(num_of_events) as events
+		        sum(num_of_requests) as requests
+		        sum(num_of_errors) as errors
+		  by token_name`
+}
+
+// cacheManagerActivityQuery summarizes SmartStore cache manager activity of the last 5 minutes by host, from metrics.log
+// queue lengths are the latest reported, other values are summed.
+func cacheManagerActivityQuery() string {
+	return `
+		search index=_internal source=*metrics.log* group=cachemgr_* earliest=-5m
+		| stats sum(cache_hit) as hits
+		        sum(cache_miss) as misses
+		        sum(evicted) as evictions
+		        sum(download_bytes) as download_bytes
+		        sum(upload_bytes) as upload_bytes
+		        latest(download_queue) as download_queue
+		        latest(upload_queue) as upload_queue
+		  by host`
+}
+
+// workloadPoolUsageQuery sums resources used by processes of each workload pool in the last minute, from introspection data
+// the latest sample of each process is used, CPU is a percentage of one core and memory is in MB.
+func workloadPoolUsageQuery() string {
+	return `
+		search index=_introspection sourcetype=splunk_resource_usage component=PerProcess data.workload_pool=* earliest=-1m
+		| rename data.* as *
+		| stats latest(pct_cpu) as pct_cpu
+		        latest(mem_used) as mem_used
+		  by host pid workload_pool
+		| stats sum(pct_cpu) as pct_cpu
+		        sum(mem_used) as mem_used
+		  by host workload_pool`
+}
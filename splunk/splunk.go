@@ -6,8 +6,10 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -50,6 +52,142 @@ func (s *Splunk) GetDimensions(index string, metric string) []string {
 	return ret
 }
 
+// GetMetricNames returns the names of metrics in index matching pattern, by alphabetical order
+// pattern accepts Splunk wildcards, for example "spl.intr.*"
+func (s *Splunk) GetMetricNames(index string, pattern string) ([]string, error) {
+	search := metricNamesQuery(index, pattern)
+	ret := make([]string, 0)
+
+	callback := func(data *SearchAPIResult, logger log.Logger) error {
+		for _, m := range data.Results {
+			ret = append(ret, m["metric_name"])
+		}
+		return nil
+	}
+
+	if err := s.query(search, callback); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// GetCardinality returns the number of distinct time series seen for one metric over the last 24 hours
+func (s *Splunk) GetCardinality(index string, metric string) (int, error) {
+	search := cardinalityQuery(index, metric)
+	cardinality := 0
+
+	callback := func(data *SearchAPIResult, logger log.Logger) error {
+		if len(data.Results) == 0 {
+			return nil
+		}
+		value, ok := data.Results[0]["cardinality"]
+		if !ok {
+			return fmt.Errorf("could not find \"cardinality\" in splunk results")
+		}
+		c, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse cardinality %q: %w", value, err)
+		}
+		cardinality = c
+		return nil
+	}
+
+	if err := s.query(search, callback); err != nil {
+		return 0, err
+	}
+	return cardinality, nil
+}
+
+// ForwarderConnection is the latest known state of a forwarder sending data to indexers
+type ForwarderConnection struct {
+	Hostname   string
+	SourceIP   string
+	Version    string
+	Type       string    // uf for universal forwarders, full for heavy forwarders, lwf for light forwarders
+	LastSeen   time.Time // last time an indexer reported a connection from the forwarder
+	Throughput float64   // bytes per second
+}
+
+// GetForwarderConnections returns forwarders that sent data in the last 24 hours, from the most recently seen
+// at most max forwarders are returned, total is the number of forwarders before this limit.
+func (s *Splunk) GetForwarderConnections(max int) (forwarders []ForwarderConnection, total int, err error) {
+	search := forwarderConnectionsQuery(max)
+	forwarders = make([]ForwarderConnection, 0)
+
+	callback := func(data *SearchAPIResult, logger log.Logger) error {
+		for _, r := range data.Results {
+			lastSeen, err := strconv.ParseFloat(r["last_seen"], 64)
+			if err != nil {
+				level.Error(logger).Log("msg", "failed to parse forwarder last seen time, ignoring it", "hostname", r["hostname"], "err", err)
+				continue
+			}
+			// throughput is missing when the forwarder only sent heartbeats
+			kbps, _ := strconv.ParseFloat(r["kbps"], 64)
+			if t, err := strconv.Atoi(r["total"]); err == nil {
+				total = t
+			}
+			forwarders = append(forwarders, ForwarderConnection{
+				Hostname:   r["hostname"],
+				SourceIP:   r["source_ip"],
+				Version:    r["version"],
+				Type:       r["fwd_type"],
+				LastSeen:   time.Unix(0, int64(lastSeen*float64(time.Second))),
+				Throughput: kbps * 1024,
+			})
+		}
+		return nil
+	}
+
+	if err := s.query(search, callback); err != nil {
+		return nil, 0, err
+	}
+	return forwarders, total, nil
+}
+
+// SchedulerActivity counts the runs of a scheduled search in the last hour, by outcome
+type SchedulerActivity struct {
+	App         string
+	SavedSearch string
+	Skipped     float64
+	Deferred    float64
+	Success     float64
+	RunTime     float64 // average duration of runs in seconds
+}
+
+// GetSchedulerActivity returns scheduled searches run in the last hour, from the most skipped
+// at most max searches are returned, total is the number of searches before this limit.
+func (s *Splunk) GetSchedulerActivity(max int) (activities []SchedulerActivity, total int, err error) {
+	search := schedulerActivityQuery(max)
+	activities = make([]SchedulerActivity, 0)
+
+	callback := func(data *SearchAPIResult, logger log.Logger) error {
+		for _, r := range data.Results {
+			if t, err := strconv.Atoi(r["total"]); err == nil {
+				total = t
+			}
+			// a field is missing or empty when no run has it, for example run_time of only skipped searches
+			skipped, _ := strconv.ParseFloat(r["skipped"], 64)
+			deferred, _ := strconv.ParseFloat(r["deferred"], 64)
+			success, _ := strconv.ParseFloat(r["success"], 64)
+			runTime, _ := strconv.ParseFloat(r["run_time"], 64)
+			activities = append(activities, SchedulerActivity{
+				App:         r["app"],
+				SavedSearch: r["savedsearch_name"],
+				Skipped:     skipped,
+				Deferred:    deferred,
+				Success:     success,
+				RunTime:     runTime,
+			})
+		}
+		return nil
+	}
+
+	if err := s.query(search, callback); err != nil {
+		return nil, 0, err
+	}
+	return activities, total, nil
+}
+
 type MetricMeasure struct {
 	Value  float64
 	Labels map[string]string
@@ -102,6 +240,265 @@ func (s *Splunk) GetMetricValues(index string, metric string, callback func(meas
 	return s.query(search, queryCallback)
 }
 
+// listPageSize is the number of entries requested per page by ListAll
+const listPageSize = 500
+
+// EntryName returns the name of the entry with the given ID
+// the title of an ID comes from the entry URL, it is still URL-escaped, like "Errors%20per%20hour" or "idx1%3A8089".
+func EntryName(id splunkclient.ID) string {
+	name, err := url.PathUnescape(id.Title)
+	if err != nil {
+		return id.Title
+	}
+	return name
+}
+
+// ListAll populates entries in place with all entries of a REST API endpoint, requesting them page by page
+// entries must be a pointer to a slice of a type tagged with its service path, like for Client.List.
+// params are added to the query string.
+func (s *Splunk) ListAll(entries interface{}, params url.Values) error {
+	return s.listAll(entries, "", params)
+}
+
+// ListAllIn is like ListAll, for endpoints listing the entries of one named object, like alerts/fired_alerts/{name}
+func (s *Splunk) ListAllIn(entries interface{}, name string, params url.Values) error {
+	return s.listAll(entries, name, params)
+}
+
+// listAll pages through entries of the service path of entries, followed by name when not empty
+func (s *Splunk) listAll(entries interface{}, name string, params url.Values) error {
+	entriesPtrV := reflect.ValueOf(entries)
+	if entriesPtrV.Kind() != reflect.Ptr || entriesPtrV.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("ListAll needs a pointer to a slice, got %T", entries)
+	}
+	entriesV := entriesPtrV.Elem()
+	entry := reflect.New(entriesV.Type().Elem()).Interface()
+
+	all := reflect.MakeSlice(entriesV.Type(), 0, 0)
+	for {
+		v := url.Values{}
+		for key, values := range params {
+			v[key] = values
+		}
+		v.Set("count", strconv.Itoa(listPageSize))
+		v.Set("offset", strconv.Itoa(all.Len()))
+
+		var data ListAPIResult
+		if err := s.get(entry, name, v, &data); err != nil {
+			return err
+		}
+		page := reflect.New(entriesV.Type())
+		if len(data.Entry) > 0 {
+			if err := json.Unmarshal(data.Entry, page.Interface()); err != nil {
+				return fmt.Errorf("could not decode entries: %w", err)
+			}
+		}
+		all = reflect.AppendSlice(all, page.Elem())
+
+		if page.Elem().Len() == 0 || all.Len() >= data.Paging.Total {
+			break
+		}
+	}
+
+	entriesV.Set(all)
+	return nil
+}
+
+// CountEntries returns the total number of entries of a REST API endpoint, without retrieving them all
+// entry is a value of a type tagged with its service path, params are added to the query string.
+func (s *Splunk) CountEntries(entry interface{}, params url.Values) (int, error) {
+	v := url.Values{}
+	for key, values := range params {
+		v[key] = values
+	}
+	v.Set("count", "1")
+
+	var data ListAPIResult
+	if err := s.get(entry, "", v, &data); err != nil {
+		return 0, err
+	}
+	return data.Paging.Total, nil
+}
+
+// get reads the REST API endpoint of entry, followed by name when not empty, and decodes the JSON response in data
+// it is meant for requests the Splunk client cannot build, when query parameters are needed.
+func (s *Splunk) get(entry interface{}, name string, params url.Values, data interface{}) error {
+	builder := func(req *http.Request) error {
+		u, err := s.Client.ServiceURL(entry)
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			u = u.JoinPath(url.PathEscape(name))
+		}
+		params.Set("output_mode", "json")
+		u.RawQuery = params.Encode()
+		req.URL = u
+
+		req.Method = http.MethodGet
+
+		level.Debug(s.Logger).Log("msg", "performing Splunk REST request", "url", u.String())
+		return s.Client.AuthenticateRequest(s.Client, req)
+	}
+	handler := func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status reading %s: %s", resp.Request.URL.Path, resp.Status)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(data); err != nil {
+			return fmt.Errorf("could not decode response of %s: %w", resp.Request.URL.Path, err)
+		}
+		return nil
+	}
+	return s.Client.RequestAndHandle(builder, handler)
+}
+
+// GetHECHealth tells whether HTTP Event Collector accepts events, with the reason given by Splunk
+// HEC is unhealthy when its queues are full.
+func (s *Splunk) GetHECHealth() (healthy bool, text string, err error) {
+	builder := func(req *http.Request) error {
+		u, err := url.Parse(fmt.Sprintf("%s/%s", s.Client.URL, "services/collector/health"))
+		if err != nil {
+			return err
+		}
+		req.URL = u
+		req.Method = http.MethodGet
+		return s.Client.AuthenticateRequest(s.Client, req)
+	}
+	handler := func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+			return fmt.Errorf("unexpected status reading HEC health: %s", resp.Status)
+		}
+		var data struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			return fmt.Errorf("could not decode HEC health: %w", err)
+		}
+		healthy = resp.StatusCode == http.StatusOK
+		text = data.Text
+		return nil
+	}
+	err = s.Client.RequestAndHandle(builder, handler)
+	return healthy, text, err
+}
+
+// HECTokenActivity sums what HTTP Event Collector received with one token in the last 5 minutes
+type HECTokenActivity struct {
+	Token    string // token name, never its value
+	Bytes    float64
+	Events   float64
+	Requests float64
+	Errors   float64
+}
+
+// GetHECTokenActivity returns what each HTTP Event Collector token received in the last 5 minutes, from introspection data
+func (s *Splunk) GetHECTokenActivity() ([]HECTokenActivity, error) {
+	search := hecTokenActivityQuery()
+	activities := make([]HECTokenActivity, 0)
+
+	callback := func(data *SearchAPIResult, logger log.Logger) error {
+		for _, r := range data.Results {
+			bytes, _ := strconv.ParseFloat(r["bytes"], 64)
+			events, _ := strconv.ParseFloat(r["events"], 64)
+			requests, _ := strconv.ParseFloat(r["requests"], 64)
+			errors, _ := strconv.ParseFloat(r["errors"], 64)
+			activities = append(activities, HECTokenActivity{
+				Token:    r["token_name"],
+				Bytes:    bytes,
+				Events:   events,
+				Requests: requests,
+				Errors:   errors,
+			})
+		}
+		return nil
+	}
+
+	if err := s.query(search, callback); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// CacheManagerActivity sums what the SmartStore cache manager of one host did in the last 5 minutes
+type CacheManagerActivity struct {
+	Host          string
+	Hits          float64 // searches finding buckets in the cache
+	Misses        float64 // searches downloading buckets from the remote store
+	Evictions     float64
+	DownloadBytes float64
+	UploadBytes   float64
+	DownloadQueue float64 // latest number of pending downloads
+	UploadQueue   float64 // latest number of pending uploads
+}
+
+// GetCacheManagerActivity returns what the SmartStore cache manager of each host did in the last 5 minutes, from metrics.log
+func (s *Splunk) GetCacheManagerActivity() ([]CacheManagerActivity, error) {
+	search := cacheManagerActivityQuery()
+	activities := make([]CacheManagerActivity, 0)
+
+	callback := func(data *SearchAPIResult, logger log.Logger) error {
+		for _, r := range data.Results {
+			// a field is empty when its metrics group was not reported
+			hits, _ := strconv.ParseFloat(r["hits"], 64)
+			misses, _ := strconv.ParseFloat(r["misses"], 64)
+			evictions, _ := strconv.ParseFloat(r["evictions"], 64)
+			downloadBytes, _ := strconv.ParseFloat(r["download_bytes"], 64)
+			uploadBytes, _ := strconv.ParseFloat(r["upload_bytes"], 64)
+			downloadQueue, _ := strconv.ParseFloat(r["download_queue"], 64)
+			uploadQueue, _ := strconv.ParseFloat(r["upload_queue"], 64)
+			activities = append(activities, CacheManagerActivity{
+				Host:          r["host"],
+				Hits:          hits,
+				Misses:        misses,
+				Evictions:     evictions,
+				DownloadBytes: downloadBytes,
+				UploadBytes:   uploadBytes,
+				DownloadQueue: downloadQueue,
+				UploadQueue:   uploadQueue,
+			})
+		}
+		return nil
+	}
+
+	if err := s.query(search, callback); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// WorkloadPoolUsage sums resources used by the processes of one workload pool on a host
+type WorkloadPoolUsage struct {
+	Host        string
+	Pool        string
+	CPUPct      float64 // percentage of one core
+	MemoryBytes float64
+}
+
+// GetWorkloadPoolUsage returns resources used by each workload pool in the last minute, from introspection data
+func (s *Splunk) GetWorkloadPoolUsage() ([]WorkloadPoolUsage, error) {
+	search := workloadPoolUsageQuery()
+	usages := make([]WorkloadPoolUsage, 0)
+
+	callback := func(data *SearchAPIResult, logger log.Logger) error {
+		for _, r := range data.Results {
+			cpu, _ := strconv.ParseFloat(r["pct_cpu"], 64)
+			mem, _ := strconv.ParseFloat(r["mem_used"], 64)
+			usages = append(usages, WorkloadPoolUsage{
+				Host:        r["host"],
+				Pool:        r["workload_pool"],
+				CPUPct:      cpu,
+				MemoryBytes: mem * 1024 * 1024,
+			})
+		}
+		return nil
+	}
+
+	if err := s.query(search, callback); err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
 // query will search splunk
 func (s *Splunk) query(search string, callbackFunc searchCallback) error {
 	level.Debug(s.Logger).Log("msg", "performing Splunk query", "search", search)
@@ -1,9 +1,15 @@
 package splunk
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,3 +51,226 @@ func TestGetDimensions_QueryErrorDoesNotHang(t *testing.T) {
 		t.Fatal("GetDimensions did not return within timeout: it deadlocked on an unclosed channel")
 	}
 }
+
+// newTestSplunk returns a Splunk wrapper whose searches are answered by handler
+func newTestSplunk(t *testing.T, handler func(search string) SearchAPIResult) *Splunk {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(body))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(handler(values.Get("search")))
+	}))
+	t.Cleanup(server.Close)
+
+	_, w, _ := os.Pipe()
+	t.Cleanup(func() { w.Close() })
+
+	client := &splunkclient.Client{
+		URL:           server.URL,
+		Authenticator: authenticators.Token{Token: "test"},
+	}
+	return &Splunk{Client: client, Logger: log.NewJSONLogger(w)}
+}
+
+func TestGetMetricNames(t *testing.T) {
+	var received string
+	s := newTestSplunk(t, func(search string) SearchAPIResult {
+		received = search
+		return SearchAPIResult{Results: []map[string]string{
+			{"metric_name": "spl.intr.disk_objects.Indexes.data.total_bucket_count"},
+			{"metric_name": "spl.intr.disk_objects.Indexes.data.total_event_count"},
+		}}
+	})
+
+	names, err := s.GetMetricNames("_metrics", "spl.intr.*")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"spl.intr.disk_objects.Indexes.data.total_bucket_count",
+		"spl.intr.disk_objects.Indexes.data.total_event_count",
+	}, names)
+	assert.True(t, strings.Contains(received, `metric_name="spl.intr.*"`))
+}
+
+func TestGetCardinality(t *testing.T) {
+	s := newTestSplunk(t, func(search string) SearchAPIResult {
+		return SearchAPIResult{Results: []map[string]string{{"cardinality": "42"}}}
+	})
+
+	c, err := s.GetCardinality("_metrics", "some.metric")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, c)
+}
+
+func TestGetCardinality_NoData(t *testing.T) {
+	s := newTestSplunk(t, func(search string) SearchAPIResult {
+		return SearchAPIResult{}
+	})
+
+	c, err := s.GetCardinality("_metrics", "some.metric")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c)
+}
+
+func TestCountEntries(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/services/cluster/manager/fixup", r.URL.Path)
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"entry": [{"name": "b1"}], "paging": {"total": 1234, "perPage": 1, "offset": 0}}`))
+	}))
+	defer server.Close()
+
+	client := &splunkclient.Client{
+		URL:           server.URL,
+		Authenticator: authenticators.Token{Token: "test"},
+	}
+	s := &Splunk{Client: client, Logger: log.NewNopLogger()}
+
+	total, err := s.CountEntries(ClusterManagerFixup{}, url.Values{"level": []string{"search_factor"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1234, total)
+	assert.Equal(t, "search_factor", query.Get("level"))
+	assert.Equal(t, "1", query.Get("count"))
+	assert.Equal(t, "json", query.Get("output_mode"))
+}
+
+func TestCountEntries_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &splunkclient.Client{
+		URL:           server.URL,
+		Authenticator: authenticators.Token{Token: "test"},
+	}
+	s := &Splunk{Client: client, Logger: log.NewNopLogger()}
+
+	_, err := s.CountEntries(ClusterManagerFixup{}, nil)
+
+	assert.Error(t, err)
+}
+
+func TestListAll(t *testing.T) {
+	peers := make([]string, 0)
+	for i := 0; i < 2*listPageSize+3; i++ {
+		peers = append(peers, fmt.Sprintf(`{"id": "https://splunk.local:8089/services/cluster/manager/peers/peer%d", "content": {"label": "idx%d"}}`, i, i))
+	}
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/services/cluster/manager/peers", r.URL.Path)
+		assert.Equal(t, "peer", r.URL.Query().Get("search"))
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := min(offset+count, len(peers))
+		fmt.Fprintf(w, `{"entry": [%s], "paging": {"total": %d, "perPage": %d, "offset": %d}}`, strings.Join(peers[offset:end], ","), len(peers), count, offset)
+	}))
+	defer server.Close()
+
+	client := &splunkclient.Client{
+		URL:           server.URL,
+		Authenticator: authenticators.Token{Token: "test"},
+	}
+	s := &Splunk{Client: client, Logger: log.NewNopLogger()}
+
+	entries := make([]ClusterManagerPeer, 0)
+	err := s.ListAll(&entries, url.Values{"search": []string{"peer"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Len(t, entries, len(peers))
+	assert.Equal(t, "peer0", entries[0].ID.Title)
+	assert.Equal(t, fmt.Sprintf("idx%d", len(peers)-1), entries[len(entries)-1].Content.Label)
+}
+
+func TestListAll_NotASlice(t *testing.T) {
+	s := &Splunk{Client: &splunkclient.Client{URL: "http://127.0.0.1:1"}, Logger: log.NewNopLogger()}
+
+	entry := ClusterManagerPeer{}
+	assert.Error(t, s.ListAll(&entry, nil))
+}
+
+func TestEntryName(t *testing.T) {
+	for id, name := range map[string]string{
+		"https://splunk.local:8089/servicesNS/nobody/search/alerts/fired_alerts/Errors%20per%20hour": "Errors per hour",
+		"https://splunk.local:8089/services/search/distributed/peers/idx1%3A8089":                    "idx1:8089",
+		"https://splunk.local:8089/services/data/indexes/main":                                       "main",
+	} {
+		parsed, err := splunkclient.ParseID(id)
+		assert.NoError(t, err)
+		assert.Equal(t, name, EntryName(parsed))
+	}
+}
+
+func TestListAllIn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the name is escaped as one path segment
+		assert.Equal(t, "/services/alerts/fired_alerts/Errors%2Fhour%20alert", r.URL.EscapedPath())
+		fmt.Fprint(w, `{"entry": [{"id": "https://splunk.local:8089/servicesNS/nobody/search/alerts/fired_alerts/scheduler__admin_at_1714640400_42", "content": {"sid": "scheduler__admin_at_1714640400_42"}}], "paging": {"total": 1, "perPage": 1, "offset": 0}}`)
+	}))
+	defer server.Close()
+
+	client := &splunkclient.Client{
+		URL:           server.URL,
+		Authenticator: authenticators.Token{Token: "test"},
+	}
+	s := &Splunk{Client: client, Logger: log.NewNopLogger()}
+
+	entries := make([]TriggeredAlert, 0)
+	err := s.ListAllIn(&entries, "Errors/hour alert", nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "scheduler__admin_at_1714640400_42", entries[0].Content.SID)
+}
+
+func TestGetForwarderConnections(t *testing.T) {
+	var received string
+	s := newTestSplunk(t, func(search string) SearchAPIResult {
+		received = search
+		return SearchAPIResult{Results: []map[string]string{
+			{"hostname": "uf1", "source_ip": "10.0.0.1", "version": "9.2.1", "fwd_type": "uf", "last_seen": "1714640400.500", "kbps": "2.5", "total": "3"},
+			{"hostname": "uf2", "source_ip": "10.0.0.2", "version": "9.0.0", "fwd_type": "uf", "last_seen": "1714640000", "total": "3"},
+			{"hostname": "broken", "last_seen": "", "total": "3"},
+		}}
+	})
+
+	forwarders, total, err := s.GetForwarderConnections(2)
+
+	assert.NoError(t, err)
+	assert.Contains(t, received, "head 2")
+	assert.Equal(t, 3, total)
+	assert.Len(t, forwarders, 2)
+	assert.Equal(t, "uf1", forwarders[0].Hostname)
+	assert.Equal(t, int64(1714640400500), forwarders[0].LastSeen.UnixMilli())
+	assert.Equal(t, 2.5*1024, forwarders[0].Throughput)
+	assert.Equal(t, "9.0.0", forwarders[1].Version)
+	assert.Equal(t, 0.0, forwarders[1].Throughput)
+}
+
+func TestGetSchedulerActivity(t *testing.T) {
+	var received string
+	s := newTestSplunk(t, func(search string) SearchAPIResult {
+		received = search
+		return SearchAPIResult{Results: []map[string]string{
+			{"app": "search", "savedsearch_name": "Hourly errors", "skipped": "3", "deferred": "1", "success": "8", "run_time": "12.5", "total": "5"},
+			{"app": "search", "savedsearch_name": "Always skipped", "skipped": "4", "deferred": "0", "success": "0", "total": "5"},
+		}}
+	})
+
+	activities, total, err := s.GetSchedulerActivity(2)
+
+	assert.NoError(t, err)
+	assert.Contains(t, received, "head 2")
+	assert.Equal(t, 5, total)
+	assert.Equal(t, []SchedulerActivity{
+		{App: "search", SavedSearch: "Hourly errors", Skipped: 3, Deferred: 1, Success: 8, RunTime: 12.5},
+		{App: "search", SavedSearch: "Always skipped", Skipped: 4},
+	}, activities)
+}
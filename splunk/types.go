@@ -0,0 +1,97 @@
+package splunk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bool is a boolean returned by Splunk REST API, which encodes them either as true/false, 0/1 or "0"/"1" depending on endpoints.
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case nil:
+		*b = false
+	case bool:
+		*b = Bool(t)
+	case float64:
+		*b = t != 0
+	case string:
+		switch strings.ToLower(t) {
+		case "1", "true", "t", "yes", "y", "on", "enabled":
+			*b = true
+		case "0", "false", "f", "no", "n", "off", "disabled", "":
+			*b = false
+		default:
+			return fmt.Errorf("cannot parse %q as a boolean", t)
+		}
+	default:
+		return fmt.Errorf("cannot parse %s as a boolean", data)
+	}
+	return nil
+}
+
+// Number is a numeric value returned by Splunk REST API, which often encodes them as strings.
+type Number float64
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case nil:
+		*n = 0
+	case float64:
+		*n = Number(t)
+	case string:
+		if t == "" {
+			*n = 0
+			return nil
+		}
+		f, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse %q as a number: %w", t, err)
+		}
+		*n = Number(f)
+	default:
+		return fmt.Errorf("cannot parse %s as a number", data)
+	}
+	return nil
+}
+
+// timeLayout is the layout of timestamps returned by Splunk REST API, for example 2024-04-30T12:00:00+0000
+const timeLayout = "2006-01-02T15:04:05-0700"
+
+// Time is a timestamp returned by Splunk REST API, it is zero when Splunk returns an empty string.
+type Time struct {
+	time.Time
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	parsed, err := time.Parse(timeLayout, *s)
+	if err != nil {
+		// some endpoints use RFC 3339, with a colon in the zone offset
+		parsed, err = time.Parse(time.RFC3339, *s)
+		if err != nil {
+			return fmt.Errorf("cannot parse %q as a time: %w", *s, err)
+		}
+	}
+	t.Time = parsed
+	return nil
+}
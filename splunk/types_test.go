@@ -0,0 +1,58 @@
+package splunk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBool(t *testing.T) {
+	for input, expected := range map[string]Bool{
+		`true`:   true,
+		`false`:  false,
+		`1`:      true,
+		`0`:      false,
+		`"1"`:    true,
+		`"0"`:    false,
+		`"true"`: true,
+		`null`:   false,
+	} {
+		var b Bool
+		assert.NoError(t, json.Unmarshal([]byte(input), &b), input)
+		assert.Equal(t, expected, b, input)
+	}
+
+	var b Bool
+	assert.Error(t, json.Unmarshal([]byte(`"maybe"`), &b))
+}
+
+func TestNumber(t *testing.T) {
+	for input, expected := range map[string]Number{
+		`12.5`:   12.5,
+		`"12.5"`: 12.5,
+		`""`:     0,
+		`null`:   0,
+	} {
+		var n Number
+		assert.NoError(t, json.Unmarshal([]byte(input), &n), input)
+		assert.Equal(t, expected, n, input)
+	}
+
+	var n Number
+	assert.Error(t, json.Unmarshal([]byte(`"twelve"`), &n))
+}
+
+func TestTime(t *testing.T) {
+	var tm Time
+	assert.NoError(t, json.Unmarshal([]byte(`"2024-04-30T12:00:00+0200"`), &tm))
+	assert.Equal(t, int64(1714471200), tm.Unix())
+
+	assert.NoError(t, json.Unmarshal([]byte(`"2024-04-30T12:00:00+02:00"`), &tm))
+	assert.Equal(t, int64(1714471200), tm.Unix())
+
+	assert.NoError(t, json.Unmarshal([]byte(`""`), &tm))
+	assert.True(t, tm.IsZero())
+
+	assert.Error(t, json.Unmarshal([]byte(`"yesterday"`), &tm))
+}